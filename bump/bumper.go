@@ -1,17 +1,22 @@
 package bump
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/version"
+	"github.com/ryancurrah/gomodbump/vuln"
 )
 
 var (
@@ -19,37 +24,104 @@ var (
 	goSumFilename = "go.sum"
 )
 
-// Configuration to use when bumping module versions.
-type Configuration struct {
-	GoModTidy      bool     `yaml:"go_mod_tidy"`
+// PseudoVersionPolicy is how modules required at a pseudo-version are updated.
+type PseudoVersionPolicy string
+
+var (
+	// LatestPseudoVersionPolicy updates to the latest version, this is the default.
+	LatestPseudoVersionPolicy PseudoVersionPolicy = "latest"
+	// FirstReleasePseudoVersionPolicy updates to the first tagged release that contains the commit.
+	FirstReleasePseudoVersionPolicy PseudoVersionPolicy = "first_release"
+)
+
+// PrereleaseConfig allows modules to be updated to prerelease versions.
+type PrereleaseConfig struct {
+	Modules         []string `yaml:"modules"`
+	Domains         []string `yaml:"domains"`
+	AllowPrerelease []string `yaml:"allow_prerelease"`
+}
+
+// ModuleFilter is the allow and block lists of modules that can be updated.
+type ModuleFilter struct {
 	AllowedModules []string `yaml:"allowed_modules"`
 	AllowedDomains []string `yaml:"allowed_domains"`
 	BlockedModules []string `yaml:"blocked_modules"`
 	BlockedDomains []string `yaml:"blocked_domains"`
 }
 
-// IsModuleAllowed returns true if the module is allowed to be updated.
-func (c Configuration) IsModuleAllowed(module string) bool {
+// Configuration to use when bumping module versions.
+type Configuration struct {
+	ModuleFilter    `yaml:",inline"`
+	GoModTidy       bool                `yaml:"go_mod_tidy"`
+	PseudoVersions  PseudoVersionPolicy `yaml:"pseudo_versions"`
+	Prereleases     []PrereleaseConfig  `yaml:"prereleases"`
+	IncludeIndirect bool                `yaml:"include_indirect"`
+	Indirect        ModuleFilter        `yaml:"indirect"`
+	Tools           ModuleFilter        `yaml:"tools"`
+	MinReleaseAge   time.Duration       `yaml:"min_release_age"`
+	Vulnerabilities vuln.Configuration  `yaml:"vulnerabilities"`
+	Gomodguard      GomodguardConfig    `yaml:"gomodguard"`
+	Constraints     map[string]string   `yaml:"constraints"`
+	Groups          []GroupConfig       `yaml:"groups"`
+	GoVersion       GoVersionConfig     `yaml:"go_version"`
+	Replace         ReplaceConfig       `yaml:"replace"`
+	Hooks           []HookConfig        `yaml:"hooks"`
+	Ignore          []IgnoreConfig      `yaml:"ignore"`
+
+	// RepositoryFilter is the allow and block lists from the repository's .gomodbump.yaml file.
+	RepositoryFilter ModuleFilter `yaml:"-"`
+
+	// TargetFilter only allows the module of a targeted bump, see Target.
+	TargetFilter ModuleFilter `yaml:"-"`
+}
+
+// GetAllowedPrereleases returns the prerelease channels the module is allowed to be updated to.
+// Module rules take precedence over domain rules.
+func (c Configuration) GetAllowedPrereleases(module string) []string {
+	for _, prerelease := range c.Prereleases {
+		for _, prereleaseModule := range prerelease.Modules {
+			if matchModulePattern(prereleaseModule, module) {
+				return prerelease.AllowPrerelease
+			}
+		}
+	}
+
+	for _, prerelease := range c.Prereleases {
+		for _, prereleaseDomain := range prerelease.Domains {
+			if matchDomain(prereleaseDomain, module) {
+				return prerelease.AllowPrerelease
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsModuleAllowed returns true if the module is allowed to be updated. Allowed modules and domains take
+// precedence over blocked ones and when there is an allow list anything not in it is blocked. Blocked modules
+// with a version only block that version, see IsVersionAllowed.
+func (c ModuleFilter) IsModuleAllowed(module string) bool {
 	for _, allowedModule := range c.AllowedModules {
-		if allowedModule == module {
+		if matchModulePattern(allowedModule, module) {
 			return true
 		}
 	}
 
 	for _, allowedDomain := range c.AllowedDomains {
-		if strings.HasPrefix(module, allowedDomain) {
+		if matchDomain(allowedDomain, module) {
 			return true
 		}
 	}
 
 	for _, blockedModule := range c.BlockedModules {
-		if blockedModule == module {
+		pattern, blockedVersion := splitModulePattern(blockedModule)
+		if blockedVersion == "" && matchModulePattern(pattern, module) {
 			return false
 		}
 	}
 
 	for _, blockedDomain := range c.BlockedDomains {
-		if strings.HasPrefix(module, blockedDomain) {
+		if matchDomain(blockedDomain, module) {
 			return false
 		}
 	}
@@ -62,22 +134,165 @@ func (c Configuration) IsModuleAllowed(module string) bool {
 	return true
 }
 
+// IsVersionAllowed returns false if the module version is blocked by a blocked module with a version
+// e.g. github.com/acme/lib@v1.5.3, even if the module is allowed.
+func (c ModuleFilter) IsVersionAllowed(module, moduleVersion string) bool {
+	for _, blockedModule := range c.BlockedModules {
+		pattern, blockedVersion := splitModulePattern(blockedModule)
+		if blockedVersion == moduleVersion && matchModulePattern(pattern, module) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasBlockedVersions returns true if any version of the module is blocked.
+func (c ModuleFilter) hasBlockedVersions(module string) bool {
+	for _, blockedModule := range c.BlockedModules {
+		pattern, blockedVersion := splitModulePattern(blockedModule)
+		if blockedVersion != "" && matchModulePattern(pattern, module) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate returns an error if any of the module patterns are invalid or an allowed module has a version.
+func (c ModuleFilter) Validate() error {
+	for _, allowedModule := range c.AllowedModules {
+		if _, allowedVersion := splitModulePattern(allowedModule); allowedVersion != "" {
+			return fmt.Errorf("invalid allowed module '%s': versions can only be blocked", allowedModule)
+		}
+
+		err := validateModulePattern(allowedModule)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, blockedModule := range c.BlockedModules {
+		pattern, _ := splitModulePattern(blockedModule)
+
+		err := validateModulePattern(pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate returns an error if any of the settings are invalid.
+func (c Configuration) Validate() error {
+	err := c.GoVersion.Validate()
+	if err != nil {
+		return err
+	}
+
+	for _, filter := range []ModuleFilter{c.ModuleFilter, c.Indirect, c.Tools} {
+		err = filter.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ignore := range c.Ignore {
+		err = ignore.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, prerelease := range c.Prereleases {
+		for _, pattern := range prerelease.Modules {
+			err = validateModulePattern(pattern)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, group := range c.Groups {
+		for _, pattern := range group.Modules {
+			err = validateModulePattern(pattern)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for module, constraint := range c.Constraints {
+		_, err = semver.NewConstraint(constraint)
+		if err != nil {
+			return fmt.Errorf("invalid constraint '%s' for module '%s': %s", constraint, module, err)
+		}
+	}
+
+	for _, hook := range c.Hooks {
+		err = hook.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	switch c.PseudoVersions {
+	case "", LatestPseudoVersionPolicy, FirstReleasePseudoVersionPolicy:
+	default:
+		return fmt.Errorf("invalid pseudo_versions '%s', expected %s or %s", c.PseudoVersions, LatestPseudoVersionPolicy, FirstReleasePseudoVersionPolicy)
+	}
+
+	return nil
+}
+
 // Bumper bumps all Go modules based on the settings provided.
 type Bumper struct {
-	conf Configuration
+	conf   Configuration
+	vulnDB *vuln.Database
 }
 
 // NewBumper initializes a new bumper.
-func NewBumper(conf Configuration) *Bumper {
-	return &Bumper{conf: conf}
+func NewBumper(conf Configuration) (*Bumper, error) {
+	err := conf.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ignore := range conf.Ignore {
+		if ignore.IsExpired(time.Now()) {
+			log.Printf("ignored versions of module %s expired on %s", ignore.Module, ignore.Until)
+		}
+	}
+
+	bumper := &Bumper{conf: conf}
+
+	if conf.Vulnerabilities.IsEnabled() {
+		vulnDB, err := vuln.NewDatabase(conf.Vulnerabilities)
+		if err != nil {
+			return nil, err
+		}
+
+		bumper.vulnDB = vulnDB
+	}
+
+	return bumper, nil
 }
 
-// Bump all the repositories Go module dependencies based on the configuration provided.
-func (b *Bumper) Bump(repo *repository.Repository) (repository.Updates, error) {
-	return b.bump(repo)
+// Bump all the repositories Go module dependencies based on the configuration provided. The configuration
+// is usually the one the bumper was initialized with merged with the repository's own configuration.
+func (b *Bumper) Bump(ctx context.Context, repo *repository.Repository, conf Configuration) (*repository.BumpResult, error) {
+	bumper := &Bumper{conf: conf}
+
+	// The configuration can disable the vulnerability database, e.g. for a targeted bump.
+	if conf.Vulnerabilities.IsEnabled() {
+		bumper.vulnDB = b.vulnDB
+	}
+
+	return bumper.bump(ctx, repo)
 }
 
-func (b *Bumper) bump(repo *repository.Repository) (repository.Updates, error) {
+func (b *Bumper) bump(ctx context.Context, repo *repository.Repository) (*repository.BumpResult, error) {
 	err := isGoModule(repo.ClonePath())
 	if err != nil {
 		log.Printf("repo '%s': has no go.mod file, skipping: %s", repo.Name, err)
@@ -85,20 +300,127 @@ func (b *Bumper) bump(repo *repository.Repository) (repository.Updates, error) {
 		return nil, nil
 	}
 
-	updates, err := getGoModuleUpdates(repo.ClonePath())
+	modules, err := getGoModules(ctx, repo.ClonePath())
+	if err != nil {
+		return nil, fmt.Errorf("repo '%s': failed to get list of module updates, skipping: %w", repo.Name, err)
+	}
+
+	goMod, err := readGoModFile(ctx, repo.ClonePath())
 	if err != nil {
-		return nil, fmt.Errorf("repo '%s': failed to get list of module updates, skipping: %s", repo.Name, err)
+		return nil, fmt.Errorf("repo '%s': failed to read go.mod file, skipping: %w", repo.Name, err)
 	}
 
-	filteredUpdates := make(repository.Updates, 0, len(updates))
+	toolModules, err := getToolModules(repo.ClonePath(), goMod, modules)
+	if err != nil {
+		return nil, fmt.Errorf("repo '%s': failed to find tools, skipping: %w", repo.Name, err)
+	}
 
-	for n := range updates {
-		if b.conf.IsModuleAllowed(updates[n].Module) {
-			filteredUpdates = append(filteredUpdates, updates[n])
+	var goVersionUpdate *repository.GoVersionUpdate
+
+	if b.conf.GoVersion.IsEnabled() {
+		goVersionUpdate, err = b.bumpGoVersion(ctx, repo, goMod)
+		if errors.Is(err, errGoVersionValidation) {
+			log.Printf("repo '%s': not updating the go directive: %s", repo.Name, err)
+		} else if err != nil {
+			return nil, fmt.Errorf("repo '%s': failed to update the go directive, skipping: %w", repo.Name, err)
+		}
+
+		if goVersionUpdate != nil {
+			log.Printf("repo '%s': updating go directive from %s to %s", repo.Name, goVersionUpdate.OldGo, goVersionUpdate.NewGo)
+		}
+	}
+
+	filteredUpdates := make(repository.Updates, 0, len(modules))
+	deprecations := make(repository.Deprecations, 0)
+
+	for n := range modules {
+		if modules[n].Main {
+			continue
+		}
+
+		// Replaced modules are handled with the replace directives, including their vulnerabilities.
+		if modules[n].Replace != nil {
+			continue
+		}
+
+		vulnerabilities, err := b.findVulnerabilities(modules[n])
+		if err != nil {
+			return nil, fmt.Errorf("repo '%s': failed to find vulnerabilities of %s, skipping: %w", repo.Name, modules[n].Path, err)
+		}
+
+		kind, ok := b.getUpdateKind(goMod, toolModules, modules[n], len(vulnerabilities) > 0)
+		if kind == "" {
+			continue
+		}
+
+		if modules[n].Deprecated != "" {
+			log.Printf("repo '%s': dependency %s is deprecated: %s", repo.Name, modules[n].Path, modules[n].Deprecated)
+
+			deprecations = append(deprecations, &repository.Deprecation{
+				Module:  modules[n].Path,
+				Message: modules[n].Deprecated,
+			})
+		}
+
+		var newVersion *version.Version
+
+		if ok && !b.conf.Vulnerabilities.SecurityOnly {
+			newVersion, err = b.resolveVersion(ctx, repo.ClonePath(), kind, modules[n])
+			if err != nil {
+				return nil, fmt.Errorf("repo '%s': failed to get the version to update %s to, skipping: %w", repo.Name, modules[n].Path, err)
+			}
+		}
+
+		// Fixing a vulnerability takes priority over the allow and block lists and the version policies.
+		fixedVersion := vulnerabilities.GetFixedVersion()
+		if fixedVersion != nil && (newVersion == nil || newVersion.LessThan(fixedVersion)) {
+			newVersion = fixedVersion
+		}
+
+		if newVersion == nil {
+			continue
+		}
+
+		retraction, _ := modules[n].getRetraction()
+
+		filteredUpdates = append(filteredUpdates, &repository.Update{
+			Module:     modules[n].Path,
+			OldVersion: modules[n].version,
+			NewVersion: newVersion,
+			Kind:       kind,
+			Retraction: retraction,
+			Advisories: getAdvisories(vulnerabilities, newVersion),
+			Group:      b.conf.GetGroup(modules[n].Path),
+		})
+	}
+
+	violations := make(repository.Violations, 0)
+
+	if b.conf.Gomodguard.Enabled {
+		filteredUpdates, violations, err = b.checkGomodguard(repo, goMod, filteredUpdates)
+		if err != nil {
+			return nil, fmt.Errorf("repo '%s': failed to check gomodguard policy, skipping: %w", repo.Name, err)
+		}
+	}
+
+	replacements, replaceUpdates, err := b.getReplacements(modules, goMod)
+	if err != nil {
+		return nil, fmt.Errorf("repo '%s': failed to check replacements, skipping: %w", repo.Name, err)
+	}
+
+	for n := range replacements {
+		if replacements[n].IsBehindUpstream() {
+			log.Printf("repo '%s': dependency %s is replaced with fork %s which is behind upstream version %s", repo.Name, replacements[n].Module, replacements[n].Replacement, replacements[n].UpstreamVersion)
+		}
+
+		if replacements[n].IsVulnerable() {
+			log.Printf("repo '%s': dependency %s is replaced with %s %s which has %d vulnerabilities", repo.Name, replacements[n].Module, replacements[n].Replacement, replacements[n].ReplacementVersion, len(replacements[n].Advisories))
 		}
 	}
 
-	if len(filteredUpdates) == 0 {
+	filteredUpdates = append(filteredUpdates, replaceUpdates...)
+
+	if len(filteredUpdates) == 0 && goVersionUpdate == nil {
 		log.Printf("repo '%s': has no updates, skipping", repo.Name)
 
 		return nil, nil
@@ -107,22 +429,238 @@ func (b *Bumper) bump(repo *repository.Repository) (repository.Updates, error) {
 	log.Printf("repo '%s': has %d dependencies that can be updated, updating", repo.Name, len(filteredUpdates))
 
 	for n := range filteredUpdates {
+		if filteredUpdates[n].IsRetracted() {
+			log.Printf("repo '%s': dependency %s version %s is retracted: %s", repo.Name, filteredUpdates[n].Module, filteredUpdates[n].OldVersion, filteredUpdates[n].Retraction)
+		}
+
 		log.Printf("repo '%s': updating dependency %s from %s to %s", repo.Name, filteredUpdates[n].Module, filteredUpdates[n].OldVersion, filteredUpdates[n].NewVersion)
 
-		err := updateGoModule(repo.ClonePath(), filteredUpdates[n].Module, *filteredUpdates[n].NewVersion)
+		if filteredUpdates[n].Kind == repository.ReplaceUpdate {
+			err = updateReplacement(ctx, repo.ClonePath(), filteredUpdates[n])
+		} else {
+			err = updateGoModule(ctx, repo.ClonePath(), filteredUpdates[n].Module, filteredUpdates[n].NewVersion)
+		}
+
 		if err != nil {
 			return nil, fmt.Errorf("repo '%s': update failed for dependency %s, skipping: %s", repo.Name, filteredUpdates[n].Module, err)
 		}
 	}
 
-	err = runGoModTidy(repo.ClonePath())
+	err = runGoModTidy(ctx, repo.ClonePath())
 	if err != nil {
 		return nil, fmt.Errorf("repo '%s': go mod tidy failed, skipping: %s", repo.Name, err)
 	}
 
+	for _, hook := range b.conf.Hooks {
+		log.Printf("repo '%s': running hook %s", repo.Name, hook.GetName())
+
+		err = runHook(ctx, repo.ClonePath(), hook)
+		if err != nil {
+			return nil, fmt.Errorf("repo '%s': %s, skipping", repo.Name, err)
+		}
+	}
+
+	newFiles, err := getNewFiles(repo.ClonePath(), b.conf.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("repo '%s': %s, skipping", repo.Name, err)
+	}
+
 	log.Printf("repo '%s': go.mod was bumped", repo.Name)
 
-	return filteredUpdates, nil
+	return &repository.BumpResult{
+		Updates:      filteredUpdates,
+		Deprecations: deprecations,
+		Violations:   violations,
+		GoVersion:    goVersionUpdate,
+		Replacements: replacements,
+		NewFiles:     newFiles,
+	}, nil
+}
+
+// getUpdateKind returns the kind of update and true if the module is allowed to be updated. Modules providing
+// tools use their own allow and block lists and so do indirect requirements, which are only updated when enabled.
+// An empty kind is returned when the module is not updated at all, a vulnerable module is always updated to the
+// version fixing it even when it is not allowed.
+func (b *Bumper) getUpdateKind(goMod *goModFile, toolModules map[string]bool, module *goListModule, vulnerable bool) (repository.UpdateKind, bool) {
+	if module.Main {
+		return "", false
+	}
+
+	if toolModules[module.Path] {
+		return repository.ToolUpdate, b.conf.IsToolModuleAllowed(module.Path)
+	}
+
+	if !module.Indirect {
+		return repository.DirectUpdate, b.conf.IsModuleAllowed(module.Path)
+	}
+
+	// Only update modules that are listed in the go.mod file as an indirect requirement. Vulnerable modules are
+	// updated even when they are not in the go.mod file.
+	if !b.conf.IncludeIndirect || goMod.getRequire(module.Path) == nil {
+		if vulnerable {
+			return repository.IndirectUpdate, false
+		}
+
+		return "", false
+	}
+
+	return repository.IndirectUpdate, b.conf.IsIndirectModuleAllowed(module.Path)
+}
+
+// checkGomodguard removes the updates that are not allowed by the gomodguard configuration and returns the
+// violations of the direct requirements before they are updated.
+func (b *Bumper) checkGomodguard(repo *repository.Repository, goMod *goModFile, updates repository.Updates) (repository.Updates, repository.Violations, error) {
+	gomodguardConf, err := loadGomodguardConfig(repo.ClonePath(), b.conf.Gomodguard)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if gomodguardConf == nil {
+		return updates, nil, nil
+	}
+
+	violations := make(repository.Violations, 0)
+
+	for _, require := range goMod.Require {
+		if require.Indirect {
+			continue
+		}
+
+		violations = append(violations, getGomodguardViolations(gomodguardConf, goMod.Module.Path, require.Path, require.Version)...)
+	}
+
+	allowedUpdates := make(repository.Updates, 0, len(updates))
+
+	for n := range updates {
+		updateViolations := getGomodguardViolations(gomodguardConf, goMod.Module.Path, updates[n].Module, updates[n].NewVersion.String())
+		if len(updateViolations) > 0 {
+			log.Printf("repo '%s': not updating dependency %s to %s, it is not allowed by gomodguard: %s", repo.Name, updates[n].Module, updates[n].NewVersion, updateViolations[0].Reason)
+
+			continue
+		}
+
+		allowedUpdates = append(allowedUpdates, updates[n])
+	}
+
+	return allowedUpdates, violations, nil
+}
+
+// findVulnerabilities returns the vulnerabilities affecting the module version if a vulnerability database is configured.
+func (b *Bumper) findVulnerabilities(module *goListModule) (vuln.Vulnerabilities, error) {
+	if b.vulnDB == nil {
+		return nil, nil
+	}
+
+	return b.vulnDB.Find(module.Path, module.version)
+}
+
+// getAdvisories returns the advisories of the vulnerabilities the new version fixes.
+func getAdvisories(vulnerabilities vuln.Vulnerabilities, newVersion *version.Version) repository.Advisories {
+	advisories := make(repository.Advisories, 0, len(vulnerabilities))
+
+	for n := range vulnerabilities {
+		if vulnerabilities[n].FixedVersion == nil || newVersion.LessThan(vulnerabilities[n].FixedVersion) {
+			continue
+		}
+
+		advisories = append(advisories, newAdvisory(vulnerabilities[n]))
+	}
+
+	return advisories
+}
+
+func newAdvisory(vulnerability *vuln.Vulnerability) *repository.Advisory {
+	return &repository.Advisory{
+		ID:       vulnerability.Entry.ID,
+		Aliases:  vulnerability.Entry.Aliases,
+		Summary:  vulnerability.Entry.Summary,
+		Severity: vulnerability.Entry.GetSeverity(),
+	}
+}
+
+// resolveVersion returns the version to update the module to based on the configuration. Versions not meeting the
+// module's constraint are never returned, retracted versions are left out of the update and the versions listed by
+// the go command, and a module required at a retracted version is moved to the highest version that is not
+// retracted, even if that is a lower version. Nil is returned when the module should not be updated.
+func (b *Bumper) resolveVersion(ctx context.Context, workingDir string, kind repository.UpdateKind, module *goListModule) (*version.Version, error) {
+	newVersion := module.update
+	versions := &moduleVersions{workingDir: workingDir, module: module.Path}
+
+	channels := b.conf.GetAllowedPrereleases(module.Path)
+	if len(channels) > 0 {
+		allVersions, err := versions.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		prerelease := getLatestPrerelease(allVersions, channels)
+		if prerelease != nil && prerelease.GreaterThan(module.version) && (newVersion == nil || prerelease.GreaterThan(newVersion)) {
+			newVersion = prerelease
+		}
+	}
+
+	if newVersion != nil && module.version.IsPseudo() && b.conf.PseudoVersions == FirstReleasePseudoVersionPolicy {
+		allVersions, err := versions.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		firstRelease := getFirstRelease(allVersions, module.version)
+		if firstRelease != nil && firstRelease.LessThan(newVersion) {
+			newVersion = firstRelease
+		}
+	}
+
+	_, oldVersionRetracted := module.getRetraction()
+
+	if newVersion == nil && !oldVersionRetracted {
+		return nil, nil
+	}
+
+	_, hasConstraint := b.conf.Constraints[module.Path]
+	hasConstraint = hasConstraint || b.conf.hasBlockedVersions(kind, module.Path) || b.conf.hasIgnoredVersions(module.Path, time.Now())
+
+	if newVersion != nil && !oldVersionRetracted && !hasConstraint && b.conf.MinReleaseAge == 0 {
+		return newVersion, nil
+	}
+
+	allVersions, err := versions.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Moving off a retracted version may require a downgrade, so there is no lower bound.
+	lowestVersion := module.version
+	if oldVersionRetracted {
+		lowestVersion = nil
+	}
+
+	candidates := make([]*version.Version, 0)
+
+	for _, candidate := range getCandidates(allVersions, lowestVersion, newVersion, channels) {
+		if !b.conf.IsVersionAllowed(kind, module.Path, candidate.String()) || b.conf.isVersionIgnored(module.Path, candidate, time.Now()) {
+			continue
+		}
+
+		satisfies, err := b.conf.satisfiesConstraint(module.Path, candidate)
+		if err != nil {
+			return nil, err
+		}
+
+		if satisfies {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	if b.conf.MinReleaseAge > 0 {
+		return getAgedVersion(ctx, workingDir, module.Path, candidates, b.conf.MinReleaseAge)
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	return candidates[0], nil
 }
 
 func fileExists(filename string) bool {
@@ -149,74 +687,112 @@ func isGoModule(workingDir string) error {
 	return nil
 }
 
-func getGoModuleUpdates(workingDir string) (repository.Updates, error) {
-	template := "{{if (and (not (or .Main .Indirect)) .Update)}}{{.Path}}:{{.Version}}:{{.Update.Version}}{{end}}"
+// getFirstRelease returns the lowest tagged release that is higher than the pseudo-version, this is
+// the first release that contains the commit the pseudo-version references. Nil is returned if there is none.
+func getFirstRelease(versions []*version.Version, pseudoVersion *version.Version) *version.Version {
+	var firstRelease *version.Version
 
-	cmd := exec.Command("go", "list", "-u", "-f", template, "-m", "all")
-
-	cmd.Dir = workingDir
-
-	cmd.Env = os.Environ()
+	for n := range versions {
+		if versions[n].IsPrerelease() || !versions[n].GreaterThan(pseudoVersion) {
+			continue
+		}
 
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("unable to find updates for Go module: %s", err)
+		if firstRelease == nil || versions[n].LessThan(firstRelease) {
+			firstRelease = versions[n]
+		}
 	}
 
-	err = cmd.Start()
-	if err != nil {
-		return nil, fmt.Errorf("unable to find updates for Go module: %s", err)
-	}
+	return firstRelease
+}
 
-	buf := new(bytes.Buffer)
+// getLatestPrerelease returns the highest version that is a release or a prerelease in one of the channels.
+func getLatestPrerelease(versions []*version.Version, channels []string) *version.Version {
+	var latest *version.Version
 
-	_, err = buf.ReadFrom(stdout)
-	if err != nil {
-		return nil, fmt.Errorf("unable to find updates for Go module: %s", err)
+	for n := range versions {
+		if versions[n].IsPrerelease() && !isPrereleaseInChannels(versions[n], channels) {
+			continue
+		}
+
+		if latest == nil || versions[n].GreaterThan(latest) {
+			latest = versions[n]
+		}
 	}
 
-	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	return latest
+}
 
-	updates := make(repository.Updates, 0, len(lines))
+// getCandidates returns the versions higher than the lowest version up to and including the highest version
+// from highest to lowest. A nil lowest or highest version means there is no bound. Prereleases are only
+// included when they are in one of the channels.
+func getCandidates(versions []*version.Version, lowestVersion, highestVersion *version.Version, channels []string) []*version.Version {
+	candidates := make([]*version.Version, 0, len(versions)+1)
 
-	for n := range lines {
-		columns := strings.Split(lines[n], ":")
+	if highestVersion != nil {
+		candidates = append(candidates, highestVersion)
+	}
 
-		if len(columns) != 3 { // nolint: gomnd
+	for n := range versions {
+		if lowestVersion != nil && !versions[n].GreaterThan(lowestVersion) {
 			continue
 		}
 
-		module := columns[0]
-		if module == "" {
+		if highestVersion != nil && !versions[n].LessThan(highestVersion) {
 			continue
 		}
 
-		oldVersion, err := semver.NewVersion(columns[1])
-		if err != nil {
-			log.Printf("invalid old module semver in '%s' for module '%s': %s", workingDir, module, err)
+		if versions[n].IsPrerelease() && !isPrereleaseInChannels(versions[n], channels) {
 			continue
 		}
 
-		newVersion, err := semver.NewVersion(columns[2])
+		candidates = append(candidates, versions[n])
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].GreaterThan(candidates[j])
+	})
+
+	return candidates
+}
+
+// getAgedVersion returns the highest candidate that was published at least the minimum age ago. Nil is
+// returned if none of the candidates are old enough.
+func getAgedVersion(ctx context.Context, workingDir, module string, candidates []*version.Version, minAge time.Duration) (*version.Version, error) {
+	for n := range candidates {
+		published, err := getModuleVersionTime(ctx, workingDir, module, candidates[n])
 		if err != nil {
-			log.Printf("invalid new module semver in '%s' for module '%s': %s", workingDir, module, err)
-			continue
+			return nil, err
 		}
 
-		updates = append(updates, &repository.Update{
-			Module:     module,
-			OldVersion: oldVersion,
-			NewVersion: newVersion,
-		})
+		if time.Since(published) >= minAge {
+			return candidates[n], nil
+		}
+
+		log.Printf("module '%s': version %s was published %s, newer than the minimum release age of %s", module, candidates[n], published.Format(time.RFC3339), minAge)
+	}
+
+	return nil, nil
+}
+
+// isPrereleaseInChannels returns true if the first prerelease identifier without its number matches a channel.
+// For example v1.2.0-rc.1 and v1.2.0-rc1 are both in the 'rc' channel.
+func isPrereleaseInChannels(moduleVersion *version.Version, channels []string) bool {
+	channel := strings.SplitN(moduleVersion.Prerelease(), ".", 2)[0] // nolint: gomnd
+	channel = strings.TrimRight(channel, "0123456789")
+
+	for n := range channels {
+		if strings.EqualFold(channel, channels[n]) {
+			return true
+		}
 	}
 
-	return updates, nil
+	return false
 }
 
-func updateGoModule(workingDir, module string, version semver.Version) error {
-	moduleVersion := fmt.Sprintf("%s@v%s", module, version.String())
+func updateGoModule(ctx context.Context, workingDir, module string, moduleVersion *version.Version) error {
+	moduleQuery := fmt.Sprintf("%s@%s", module, moduleVersion)
 
-	cmd := exec.Command("go", "get", moduleVersion)
+	cmd := exec.CommandContext(ctx, "go", "get", moduleQuery)
 
 	cmd.Dir = workingDir
 
@@ -240,8 +816,8 @@ func updateGoModule(workingDir, module string, version semver.Version) error {
 	return nil
 }
 
-func runGoModTidy(workingDir string) error {
-	cmd := exec.Command("go", "mod", "tidy")
+func runGoModTidy(ctx context.Context, workingDir string) error {
+	cmd := exec.CommandContext(ctx, "go", "mod", "tidy")
 
 	cmd.Dir = workingDir
 
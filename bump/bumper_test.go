@@ -0,0 +1,119 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
+func TestBumperGetUpdateKind(t *testing.T) {
+	requires := []string{"github.com/acme/lib", "github.com/acme/indirect", "github.com/acme/blocked"}
+	tools := []string{"github.com/acme/tool"}
+
+	var tests = []struct {
+		testName        string
+		includeIndirect bool
+		module          string
+		indirect        bool
+		vulnerable      bool
+		wantKind        repository.UpdateKind
+		wantAllowed     bool
+	}{
+		{"should update a direct requirement", false, "github.com/acme/lib", false, false, repository.DirectUpdate, true},
+		{"should update a tool", false, "github.com/acme/tool", false, false, repository.ToolUpdate, true},
+		{"should update an indirect requirement when enabled", true, "github.com/acme/indirect", true, false, repository.IndirectUpdate, true},
+		{"should not update an indirect requirement when disabled", false, "github.com/acme/indirect", true, false, "", false},
+		{"should not update an indirect module missing from the go.mod file", true, "github.com/acme/missing", true, false, "", false},
+		{"should check the indirect lists of an indirect requirement", true, "github.com/acme/blocked", true, false, repository.IndirectUpdate, false},
+		{"should update a vulnerable indirect requirement when disabled", false, "github.com/acme/indirect", true, true, repository.IndirectUpdate, false},
+		{"should update a vulnerable indirect module missing from the go.mod file", true, "github.com/acme/missing", true, true, repository.IndirectUpdate, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			bumper, err := bump.NewBumper(bump.Configuration{
+				IncludeIndirect: tt.includeIndirect,
+				Indirect:        bump.ModuleFilter{BlockedModules: []string{"github.com/acme/blocked"}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kind, allowed := bumper.GetUpdateKind(requires, tools, tt.module, tt.indirect, tt.vulnerable)
+			if kind != tt.wantKind {
+				t.Errorf("got kind '%v' want '%v'", kind, tt.wantKind)
+			}
+
+			if allowed != tt.wantAllowed {
+				t.Errorf("got allowed '%v' want '%v'", allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestBumperResolveVersionMinReleaseAge(t *testing.T) {
+	// The versions of github.com/acme/lib and how long ago they were published.
+	published := map[string]time.Duration{
+		"v1.0.0": 30 * 24 * time.Hour,
+		"v1.1.0": 10 * 24 * time.Hour,
+		"v1.2.0": 24 * time.Hour,
+	}
+
+	restore := bump.SetGoList(func(ctx context.Context, workingDir string, args ...string) ([]byte, error) {
+		query := args[len(args)-1]
+
+		if strings.Join(args[:len(args)-1], " ") == "-m -versions -json" {
+			return json.Marshal(map[string]interface{}{"Path": query, "Versions": []string{"v1.0.0", "v1.1.0", "v1.2.0"}})
+		}
+
+		parts := strings.SplitN(query, "@", 2)
+
+		age, ok := published[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown version %s", query)
+		}
+
+		return json.Marshal(map[string]interface{}{"Path": parts[0], "Version": parts[1], "Time": time.Now().Add(-age)})
+	})
+	defer restore()
+
+	var tests = []struct {
+		testName      string
+		minReleaseAge time.Duration
+		wantVersion   string
+	}{
+		{"should update to the newest version when it is old enough", 12 * time.Hour, "v1.2.0"},
+		{"should update to an older version when the newest is too young", 72 * time.Hour, "v1.1.0"},
+		{"should not update when no version is old enough", 20 * 24 * time.Hour, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			bumper, err := bump.NewBumper(bump.Configuration{MinReleaseAge: tt.minReleaseAge})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			newVersion, err := bumper.ResolveVersion(context.Background(), "github.com/acme/lib", "v1.0.0", "v1.2.0")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := ""
+			if newVersion != nil {
+				got = newVersion.String()
+			}
+
+			if got != tt.wantVersion {
+				t.Errorf("got '%v' want '%v'", got, tt.wantVersion)
+			}
+		})
+	}
+}
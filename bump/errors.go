@@ -0,0 +1,122 @@
+package bump
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiscoveryErrorKind is the kind of failure that occurred while finding module updates.
+type DiscoveryErrorKind string
+
+var (
+	// AuthError the go command could not authenticate against a module source.
+	AuthError DiscoveryErrorKind = "auth"
+	// NetworkError the go command could not reach a module source.
+	NetworkError DiscoveryErrorKind = "network"
+	// UnknownRevisionError a required module version does not exist in the module source.
+	UnknownRevisionError DiscoveryErrorKind = "unknown revision"
+	// ChecksumMismatchError a module did not match the checksum in go.sum or the checksum database.
+	ChecksumMismatchError DiscoveryErrorKind = "checksum mismatch"
+	// UnknownError the failure could not be classified.
+	UnknownError DiscoveryErrorKind = "unknown"
+)
+
+// The order matters, the go command reports a private module it could not authenticate for as an invalid version.
+// Only a mismatch is a checksum error, the checksum database failing to look up a module, e.g. with a 404 or 410
+// status, is classified by that status.
+var discoveryErrorPatterns = []struct {
+	kind     DiscoveryErrorKind
+	patterns []string
+}{
+	{
+		kind: ChecksumMismatchError,
+		patterns: []string{
+			"checksum mismatch",
+			"security error",
+		},
+	},
+	{
+		kind: AuthError,
+		patterns: []string{
+			"terminal prompts disabled",
+			"authentication required",
+			"authentication failed",
+			"could not read username",
+			"could not read password",
+			"permission denied",
+			"401 unauthorized",
+			"403 forbidden",
+		},
+	},
+	{
+		kind: UnknownRevisionError,
+		patterns: []string{
+			"unknown revision",
+			"invalid version",
+			"no matching versions",
+			"not available",
+			"404 not found",
+			"410 gone",
+		},
+	},
+	{
+		kind: NetworkError,
+		patterns: []string{
+			"dial tcp",
+			"no such host",
+			"i/o timeout",
+			"connection refused",
+			"connection reset",
+			"network is unreachable",
+			"tls handshake timeout",
+			"502 bad gateway",
+			"503 service unavailable",
+			"504 gateway timeout",
+		},
+	},
+}
+
+// DiscoveryError is returned when the go command fails to find module updates.
+type DiscoveryError struct {
+	Kind   DiscoveryErrorKind
+	Stderr string
+	Err    error
+}
+
+// NewDiscoveryError classifies the stderr output of a failed go command.
+func NewDiscoveryError(stderr string, err error) *DiscoveryError {
+	stderr = strings.TrimSpace(stderr)
+
+	return &DiscoveryError{
+		Kind:   classifyDiscoveryError(stderr),
+		Stderr: stderr,
+		Err:    err,
+	}
+}
+
+func (e *DiscoveryError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("%s error: %s", e.Kind, e.Err)
+	}
+
+	return fmt.Sprintf("%s error: %s: %s", e.Kind, e.Stderr, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DiscoveryError) Unwrap() error {
+	return e.Err
+}
+
+func classifyDiscoveryError(stderr string) DiscoveryErrorKind {
+	stderr = strings.ToLower(stderr)
+
+	for _, discoveryErrorPattern := range discoveryErrorPatterns {
+		for _, pattern := range discoveryErrorPattern.patterns {
+			if strings.Contains(stderr, pattern) {
+				return discoveryErrorPattern.kind
+			}
+		}
+	}
+
+	return UnknownError
+}
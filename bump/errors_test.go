@@ -0,0 +1,75 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+func TestNewDiscoveryError(t *testing.T) {
+	var tests = []struct {
+		testName string
+		stderr   string
+		wantKind bump.DiscoveryErrorKind
+	}{
+		{
+			"should classify a git credential prompt as an auth error",
+			"go: git.acme.com/lib@v1.0.0: invalid version: git ls-remote -q origin in /root/go/pkg/mod/cache/vcs/4b1d2f: exit status 128:\n" +
+				"\tfatal: could not read Username for 'https://git.acme.com': terminal prompts disabled\n" +
+				"Confirm the import path was entered correctly.\n" +
+				"If this is a private repository, see https://golang.org/doc/faq#git_https for additional information.",
+			bump.AuthError,
+		},
+		{
+			"should classify a dns failure as a network error",
+			"go: git.acme.com/lib@v1.0.0: dial tcp: lookup git.acme.com: no such host",
+			bump.NetworkError,
+		},
+		{
+			"should classify a missing version as an unknown revision error",
+			"go: git.acme.com/lib@v1.0.1: invalid version: unknown revision v1.0.1",
+			bump.UnknownRevisionError,
+		},
+		{
+			"should classify a checksum mismatch",
+			"verifying git.acme.com/lib@v1.0.0: checksum mismatch\n" +
+				"\tdownloaded: h1:rBnC8BtM6rJ6RbXd5dOW2zFSAXkq4sYKqSc3QFFVR4E=\n" +
+				"\tgo.sum:     h1:J2Y4nLlNG2DCPyxhiHW2PLLkvEDH4Ry0L3IXJzlrpWQ=\n\n" +
+				"SECURITY ERROR\n" +
+				"This download does NOT match an earlier download recorded in go.sum.",
+			bump.ChecksumMismatchError,
+		},
+		{
+			"should classify a module missing from the checksum database by the http status",
+			"go: verifying module: git.acme.com/lib@v1.0.0: reading https://sum.golang.org/lookup/git.acme.com/lib@v1.0.0: 410 Gone",
+			bump.UnknownRevisionError,
+		},
+		{
+			"should classify the checksum database denying access as an auth error",
+			"go: verifying module: git.acme.com/lib@v1.0.0: reading https://sum.golang.org/lookup/git.acme.com/lib@v1.0.0: 403 Forbidden",
+			bump.AuthError,
+		},
+		{
+			"should classify anything else as unknown",
+			"go: something unexpected happened",
+			bump.UnknownError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := errors.New("exit status 1")
+
+			discoveryErr := bump.NewDiscoveryError(tt.stderr, err)
+			if discoveryErr.Kind != tt.wantKind {
+				t.Errorf("got '%v' want '%v'", discoveryErr.Kind, tt.wantKind)
+			}
+
+			if !errors.Is(discoveryErr, err) {
+				t.Errorf("got '%v' want it to wrap '%v'", discoveryErr, err)
+			}
+		})
+	}
+}
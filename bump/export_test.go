@@ -0,0 +1,149 @@
+package bump
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+// ParseGoModules exposes parseGoModules to the bump_test package.
+func ParseGoModules(stdout []byte) ([]*goListModule, error) {
+	return parseGoModules("", stdout)
+}
+
+// GetRetraction exposes getRetraction to the bump_test package.
+func (m *goListModule) GetRetraction() (string, bool) {
+	return m.getRetraction()
+}
+
+// CheckGomodguard exposes checkGomodguard to the bump_test package. The requires are the direct requirements of
+// the module formatted as module@version.
+func (b *Bumper) CheckGomodguard(repo *repository.Repository, module string, requires []string, updates repository.Updates) (repository.Updates, repository.Violations, error) {
+	goMod := &goModFile{Module: goModModule{Path: module}}
+
+	for _, require := range requires {
+		parts := strings.SplitN(require, "@", 2)
+		goMod.Require = append(goMod.Require, goModRequire{Path: parts[0], Version: parts[1]})
+	}
+
+	return b.checkGomodguard(repo, goMod, updates)
+}
+
+// GetGoVersionUpdate exposes getGoVersionUpdate to the bump_test package.
+func GetGoVersionUpdate(conf GoVersionConfig, goDirective, toolchain string) (*repository.GoVersionUpdate, error) {
+	return getGoVersionUpdate(conf, &goModFile{Go: goDirective, Toolchain: toolchain})
+}
+
+// IsGoVersionLower exposes isGoVersionLower to the bump_test package.
+var IsGoVersionLower = isGoVersionLower
+
+// GetReplacements exposes getReplacements to the bump_test package, the modules are parsed from the
+// `go list -u -m -json all` output and the go.mod file is read from the working directory.
+func (b *Bumper) GetReplacements(ctx context.Context, workingDir string, goListOutput []byte) (repository.Replacements, repository.Updates, error) {
+	modules, err := parseGoModules(workingDir, goListOutput)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	goMod, err := readGoModFile(ctx, workingDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b.getReplacements(modules, goMod)
+}
+
+// UpdateReplacement exposes updateReplacement to the bump_test package.
+var UpdateReplacement = updateReplacement
+
+// GetToolModules exposes getToolModules to the bump_test package, the tools are the tool directives of the go.mod
+// file and the modules are parsed from the `go list -u -m -json all` output.
+func GetToolModules(workingDir string, tools []string, goListOutput []byte) (map[string]bool, error) {
+	modules, err := parseGoModules(workingDir, goListOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	goMod := &goModFile{}
+
+	for _, tool := range tools {
+		goMod.Tool = append(goMod.Tool, goModTool{Path: tool})
+	}
+
+	return getToolModules(workingDir, goMod, modules)
+}
+
+// CompileRegexPattern exposes compileRegexPattern to the bump_test package.
+var CompileRegexPattern = compileRegexPattern
+
+// GetLatestPrerelease exposes getLatestPrerelease to the bump_test package.
+var GetLatestPrerelease = getLatestPrerelease
+
+// GetCandidates exposes getCandidates to the bump_test package.
+var GetCandidates = getCandidates
+
+// IsPrereleaseInChannels exposes isPrereleaseInChannels to the bump_test package.
+var IsPrereleaseInChannels = isPrereleaseInChannels
+
+// SatisfiesConstraint exposes satisfiesConstraint to the bump_test package.
+func (c Configuration) SatisfiesConstraint(module string, moduleVersion *version.Version) (bool, error) {
+	return c.satisfiesConstraint(module, moduleVersion)
+}
+
+// GetUpdateKind exposes getUpdateKind to the bump_test package. The requires are the requirements of the go.mod
+// file and the tools the modules providing tools.
+func (b *Bumper) GetUpdateKind(requires, tools []string, module string, indirect, vulnerable bool) (repository.UpdateKind, bool) {
+	goMod := &goModFile{}
+
+	for _, require := range requires {
+		goMod.Require = append(goMod.Require, goModRequire{Path: require})
+	}
+
+	toolModules := make(map[string]bool, len(tools))
+
+	for _, tool := range tools {
+		toolModules[tool] = true
+	}
+
+	return b.getUpdateKind(goMod, toolModules, &goListModule{Path: module, Indirect: indirect}, vulnerable)
+}
+
+// SetGoList replaces how `go list` is run for the bump_test package and returns a function restoring it.
+func SetGoList(goList func(ctx context.Context, workingDir string, args ...string) ([]byte, error)) func() {
+	previous := runGoList
+	runGoList = goList
+
+	return func() {
+		runGoList = previous
+	}
+}
+
+// ResolveVersion exposes resolveVersion to the bump_test package for a direct requirement of the module at the
+// version, the update is the version `go list -u` would update it to.
+func (b *Bumper) ResolveVersion(ctx context.Context, module, moduleVersion, update string) (*version.Version, error) {
+	listModule := &goListModule{Path: module, Version: moduleVersion}
+
+	var err error
+
+	listModule.version, err = version.Parse(moduleVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if update != "" {
+		listModule.update, err = version.Parse(update)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.resolveVersion(ctx, "", repository.DirectUpdate, listModule)
+}
+
+// IsVersionIgnored exposes isVersionIgnored to the bump_test package.
+func (c Configuration) IsVersionIgnored(module string, moduleVersion *version.Version, now time.Time) bool {
+	return c.isVersionIgnored(module, moduleVersion, now)
+}
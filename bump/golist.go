@@ -0,0 +1,215 @@
+package bump
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+// goListModule is the subset of the `go list -m -json` output used by the bumper.
+type goListModule struct {
+	Path     string
+	Version  string
+	Versions []string
+	Time     *time.Time
+	Main     bool
+	Indirect bool
+	Update   *goListModule
+	Replace  *goListModule
+	GoMod    string
+	Error    *goListModuleError
+
+	// Retracted is set with the rationales when the required version is retracted and Deprecated with the
+	// deprecation message of the latest version, both require the -u flag.
+	Retracted  []string
+	Deprecated string
+
+	version *version.Version
+	update  *version.Version
+}
+
+// goListModuleError is the error of a module that could not be loaded, e.g. the version does not exist.
+type goListModuleError struct {
+	Err string
+}
+
+// runGoCommand runs the go command in the working directory and returns stdout. When the command
+// fails a *DiscoveryError is returned so failures reaching the module sources can be reported, unless it
+// failed because the context was cancelled.
+func runGoCommand(ctx context.Context, workingDir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+
+	cmd.Dir = workingDir
+
+	cmd.Env = os.Environ()
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if err != nil {
+		return nil, NewDiscoveryError(stderr.String(), err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// runGoList runs `go list` with the arguments in the working directory, the tests replace it so no module proxy
+// is needed.
+var runGoList = func(ctx context.Context, workingDir string, args ...string) ([]byte, error) {
+	return runGoCommand(ctx, workingDir, append([]string{"list"}, args...)...)
+}
+
+// getGoModules returns the modules in the build list with the version available to update to.
+func getGoModules(ctx context.Context, workingDir string) ([]*goListModule, error) {
+	stdout, err := runGoList(ctx, workingDir, "-u", "-m", "-json", "all")
+	if err != nil {
+		return nil, fmt.Errorf("unable to find updates for Go module: %w", err)
+	}
+
+	return parseGoModules(workingDir, stdout)
+}
+
+// parseGoModules parses the modules from the `go list -u -m -json all` output, modules with invalid versions
+// are skipped.
+func parseGoModules(workingDir string, stdout []byte) ([]*goListModule, error) {
+	modules := make([]*goListModule, 0)
+
+	decoder := json.NewDecoder(bytes.NewReader(stdout))
+
+	for {
+		module := &goListModule{}
+
+		err := decoder.Decode(module)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to find updates for Go module: %s", err)
+		}
+
+		if module.Main {
+			modules = append(modules, module)
+			continue
+		}
+
+		module.version, err = version.Parse(module.Version)
+		if err != nil {
+			log.Printf("invalid old module version in '%s' for module '%s': %s", workingDir, module.Path, err)
+			continue
+		}
+
+		if module.Update != nil {
+			module.update, err = version.Parse(module.Update.Version)
+			if err != nil {
+				log.Printf("invalid new module version in '%s' for module '%s': %s", workingDir, module.Path, err)
+				continue
+			}
+		}
+
+		modules = append(modules, module)
+	}
+
+	return modules, nil
+}
+
+// getModuleVersions returns the tagged versions of a module known to the module proxy or source, the go command
+// leaves out retracted versions.
+func getModuleVersions(ctx context.Context, workingDir, module string) ([]*version.Version, error) {
+	stdout, err := runGoList(ctx, workingDir, "-m", "-versions", "-json", module)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list versions of module '%s': %w", module, err)
+	}
+
+	listModule := goListModule{}
+
+	err = json.Unmarshal(stdout, &listModule)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list versions of module '%s': %s", module, err)
+	}
+
+	versions := make([]*version.Version, 0, len(listModule.Versions))
+
+	for n := range listModule.Versions {
+		moduleVersion, err := version.Parse(listModule.Versions[n])
+		if err != nil {
+			log.Printf("invalid module version '%s' for module '%s': %s", listModule.Versions[n], module, err)
+			continue
+		}
+
+		versions = append(versions, moduleVersion)
+	}
+
+	return versions, nil
+}
+
+// moduleVersions lists the versions of a module once, no matter how many times they are needed.
+type moduleVersions struct {
+	workingDir string
+	module     string
+	versions   []*version.Version
+	listed     bool
+}
+
+func (m *moduleVersions) get(ctx context.Context) ([]*version.Version, error) {
+	if m.listed {
+		return m.versions, nil
+	}
+
+	versions, err := getModuleVersions(ctx, m.workingDir, m.module)
+	if err != nil {
+		return nil, err
+	}
+
+	m.versions = versions
+	m.listed = true
+
+	return m.versions, nil
+}
+
+// getModuleVersionTime returns the time the module version was published according to the module proxy '.info' file.
+func getModuleVersionTime(ctx context.Context, workingDir, module string, moduleVersion *version.Version) (time.Time, error) {
+	stdout, err := runGoList(ctx, workingDir, "-m", "-json", fmt.Sprintf("%s@%s", module, moduleVersion))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get info of module '%s@%s': %w", module, moduleVersion, err)
+	}
+
+	listModule := goListModule{}
+
+	err = json.Unmarshal(stdout, &listModule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get info of module '%s@%s': %s", module, moduleVersion, err)
+	}
+
+	if listModule.Time == nil {
+		return time.Time{}, fmt.Errorf("unable to get info of module '%s@%s': no publish time", module, moduleVersion)
+	}
+
+	return *listModule.Time, nil
+}
+
+// getRetraction returns the rationale and true if the required version of the module is retracted.
+func (m *goListModule) getRetraction() (string, bool) {
+	if len(m.Retracted) == 0 {
+		return "", false
+	}
+
+	return strings.Join(m.Retracted, "; "), true
+}
@@ -0,0 +1,105 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+const goListOutput = `{
+	"Path": "git.acme.com/api",
+	"Main": true,
+	"GoMod": "/src/api/go.mod"
+}
+{
+	"Path": "git.acme.com/lib",
+	"Version": "v1.2.0",
+	"Update": {
+		"Path": "git.acme.com/lib",
+		"Version": "v1.3.0"
+	},
+	"Retracted": [
+		"data race in the connection pool"
+	]
+}
+{
+	"Path": "git.acme.com/legacy",
+	"Version": "v0.4.0",
+	"Deprecated": "use git.acme.com/lib instead."
+}
+{
+	"Path": "git.acme.com/broken",
+	"Version": "v1.2.0",
+	"Retracted": [
+		"retracted by module author",
+		"wrong module path"
+	]
+}
+{
+	"Path": "git.acme.com/invalid",
+	"Version": "latest"
+}
+{
+	"Path": "git.acme.com/gone",
+	"Version": "v1.0.0",
+	"Error": {
+		"Err": "git.acme.com/gone@v1.0.0: reading https://proxy.golang.org/git.acme.com/gone/@v/v1.0.0.info: 410 Gone"
+	}
+}
+`
+
+func TestParseGoModules(t *testing.T) {
+	modules, err := bump.ParseGoModules([]byte(goListOutput))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(modules) != 5 {
+		t.Fatalf("got %d modules want 5, modules with invalid versions are skipped", len(modules))
+	}
+
+	var tests = []struct {
+		testName       string
+		module         int
+		wantPath       string
+		wantRetraction string
+		wantRetracted  bool
+		wantDeprecated string
+		wantErr        string
+	}{
+		{"should parse the main module", 0, "git.acme.com/api", "", false, "", ""},
+		{"should parse a retracted version", 1, "git.acme.com/lib", "data race in the connection pool", true, "", ""},
+		{"should parse a deprecated module", 2, "git.acme.com/legacy", "", false, "use git.acme.com/lib instead.", ""},
+		{"should join the rationales of a version retracted twice", 3, "git.acme.com/broken", "retracted by module author; wrong module path", true, "", ""},
+		{"should parse the error of a module", 4, "git.acme.com/gone", "", false, "", "git.acme.com/gone@v1.0.0: reading https://proxy.golang.org/git.acme.com/gone/@v/v1.0.0.info: 410 Gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			module := modules[tt.module]
+
+			if module.Path != tt.wantPath {
+				t.Errorf("got path '%s' want '%s'", module.Path, tt.wantPath)
+			}
+
+			retraction, retracted := module.GetRetraction()
+			if retraction != tt.wantRetraction || retracted != tt.wantRetracted {
+				t.Errorf("got retraction '%s' '%v' want '%s' '%v'", retraction, retracted, tt.wantRetraction, tt.wantRetracted)
+			}
+
+			if module.Deprecated != tt.wantDeprecated {
+				t.Errorf("got deprecation '%s' want '%s'", module.Deprecated, tt.wantDeprecated)
+			}
+
+			moduleErr := ""
+			if module.Error != nil {
+				moduleErr = module.Error.Err
+			}
+
+			if moduleErr != tt.wantErr {
+				t.Errorf("got error '%s' want '%s'", moduleErr, tt.wantErr)
+			}
+		})
+	}
+}
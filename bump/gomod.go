@@ -0,0 +1,210 @@
+package bump
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// goModFile is the subset of the `go mod edit -json` output used by the bumper.
+type goModFile struct {
+	Module    goModModule
+	Go        string
+	Toolchain string
+	Require   []goModRequire
+	Replace   []goModReplace
+	Tool      []goModTool
+}
+
+type goModModule struct {
+	Path string
+}
+
+type goModTool struct {
+	Path string
+}
+
+type goModReplace struct {
+	Old goModModuleVersion
+	New goModModuleVersion
+}
+
+type goModModuleVersion struct {
+	Path    string
+	Version string
+}
+
+type goModRequire struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// Module is the path of a Go module and the modules it requires.
+type Module struct {
+	Path     string
+	Requires []string
+}
+
+// ReadModule reads the path and requirements of the Go module in the working directory.
+func ReadModule(ctx context.Context, workingDir string) (*Module, error) {
+	goMod, err := readGoModFile(ctx, workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	module := &Module{Path: goMod.Module.Path, Requires: make([]string, 0, len(goMod.Require))}
+
+	for _, require := range goMod.Require {
+		module.Requires = append(module.Requires, require.Path)
+	}
+
+	return module, nil
+}
+
+// HasRequire returns true if the module requires the other module.
+func (m *Module) HasRequire(module string) bool {
+	for _, require := range m.Requires {
+		if require == module {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseModulePath returns the module path declared by the contents of a go.mod file. It does not need the go
+// command so it can be used on go.mod files that are not checked out.
+func ParseModulePath(goMod []byte) (string, error) {
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := getGoModFields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+
+		return unquoteModulePath(fields[1])
+	}
+
+	return "", errors.New("go.mod file has no module directive")
+}
+
+// ParseModule returns the module path and the modules required by the contents of a go.mod file. Like
+// ParseModulePath it does not need the go command.
+func ParseModule(goMod []byte) (*Module, error) {
+	modulePath, err := ParseModulePath(goMod)
+	if err != nil {
+		return nil, err
+	}
+
+	module := &Module{Path: modulePath}
+	inRequireBlock := false
+
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := getGoModFields(line)
+
+		var require string
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequireBlock && fields[0] == ")":
+			inRequireBlock = false
+			continue
+		case inRequireBlock:
+			require = fields[0]
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequireBlock = true
+			continue
+		case fields[0] == "require" && len(fields) == 3:
+			require = fields[1]
+		default:
+			continue
+		}
+
+		requirePath, err := unquoteModulePath(require)
+		if err != nil {
+			return nil, err
+		}
+
+		module.Requires = append(module.Requires, requirePath)
+	}
+
+	return module, nil
+}
+
+// getGoModFields returns the fields of a line of a go.mod file without its comment.
+func getGoModFields(line string) []string {
+	if n := strings.Index(line, "//"); n != -1 {
+		line = line[:n]
+	}
+
+	return strings.Fields(line)
+}
+
+// unquoteModulePath returns the module path of a go.mod file without its quotes, if it is quoted.
+func unquoteModulePath(modulePath string) (string, error) {
+	if !strings.HasPrefix(modulePath, "\"") && !strings.HasPrefix(modulePath, "`") {
+		return modulePath, nil
+	}
+
+	unquoted, err := strconv.Unquote(modulePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid module path %s in go.mod file: %s", modulePath, err)
+	}
+
+	return unquoted, nil
+}
+
+// readGoModFile parses the go.mod file in the working directory using the go command.
+func readGoModFile(ctx context.Context, workingDir string) (*goModFile, error) {
+	stdout, err := runGoCommand(ctx, workingDir, "mod", "edit", "-json", filepath.Join(workingDir, goModFilename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read go.mod file: %w", err)
+	}
+
+	goMod := &goModFile{}
+
+	err = json.Unmarshal(stdout, goMod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read go.mod file: %s", err)
+	}
+
+	return goMod, nil
+}
+
+// getRequire returns the requirement for the module or nil if the go.mod file does not require it.
+func (f *goModFile) getRequire(module string) *goModRequire {
+	for n := range f.Require {
+		if f.Require[n].Path == module {
+			return &f.Require[n]
+		}
+	}
+
+	return nil
+}
+
+// getReplace returns the replace directive of the module or nil if the module is not replaced. A directive
+// for the version takes precedence over one for all versions.
+func (f *goModFile) getReplace(module, moduleVersion string) *goModReplace {
+	var replace *goModReplace
+
+	for n := range f.Replace {
+		if f.Replace[n].Old.Path != module {
+			continue
+		}
+
+		if f.Replace[n].Old.Version == moduleVersion {
+			return &f.Replace[n]
+		}
+
+		if f.Replace[n].Old.Version == "" {
+			replace = &f.Replace[n]
+		}
+	}
+
+	return replace
+}
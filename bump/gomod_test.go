@@ -0,0 +1,78 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+func TestParseModulePath(t *testing.T) {
+	var tests = []struct {
+		testName string
+		goMod    string
+		want     string
+		wantErr  bool
+	}{
+		{"should parse the module directive", "module git.acme.com/lib\n\ngo 1.21\n", "git.acme.com/lib", false},
+		{"should parse a module directive after comments", "// Deprecated: use lib/v2.\nmodule git.acme.com/lib // main module\n", "git.acme.com/lib", false},
+		{"should parse a quoted module path", "module \"git.acme.com/lib/v2\"\n", "git.acme.com/lib/v2", false},
+		{"should parse windows line endings", "module git.acme.com/lib\r\ngo 1.21\r\n", "git.acme.com/lib", false},
+		{"should require a module directive", "go 1.21\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			got, err := bump.ParseModulePath([]byte(tt.goMod))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error '%v' want error %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got '%s' want '%s'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseModule(t *testing.T) {
+	var tests = []struct {
+		testName     string
+		goMod        string
+		wantRequires []string
+		wantErr      bool
+	}{
+		{"should parse a require directive", "module git.acme.com/api\n\nrequire git.acme.com/lib v1.0.0\n", []string{"git.acme.com/lib"}, false},
+		{
+			"should parse a require block",
+			"module git.acme.com/api\n\nrequire (\n\tgit.acme.com/lib v1.0.0\n\t// pinned\n\tgit.acme.com/db v1.1.0 // indirect\n)\n",
+			[]string{"git.acme.com/lib", "git.acme.com/db"},
+			false,
+		},
+		{"should parse a quoted require", "module git.acme.com/api\n\nrequire \"git.acme.com/lib\" v1.0.0\n", []string{"git.acme.com/lib"}, false},
+		{"should not parse the other directives", "module git.acme.com/api\n\ngo 1.21\n\nreplace git.acme.com/lib => ../lib\n", nil, false},
+		{"should require a module directive", "require git.acme.com/lib v1.0.0\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			module, err := bump.ParseModule([]byte(tt.goMod))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error '%v' want error %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if module.Path != "git.acme.com/api" {
+				t.Errorf("got path '%s' want '%s'", module.Path, "git.acme.com/api")
+			}
+
+			if !reflect.DeepEqual(module.Requires, tt.wantRequires) {
+				t.Errorf("got '%v' want '%v'", module.Requires, tt.wantRequires)
+			}
+		})
+	}
+}
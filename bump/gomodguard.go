@@ -0,0 +1,86 @@
+package bump
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodguard"
+	"gopkg.in/yaml.v2"
+)
+
+const gomodguardFilename = ".gomodguard.yaml"
+
+// GomodguardConfig checks updates against a gomodguard configuration so bumps do not fail linting.
+type GomodguardConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	ConfigFile string `yaml:"config_file"`
+}
+
+// loadGomodguardConfig returns the repository's .gomodguard.yaml file, or the central configuration file if the
+// repository does not have one. Nil is returned if neither exist.
+func loadGomodguardConfig(workingDir string, conf GomodguardConfig) (*gomodguard.Configuration, error) {
+	configFile := filepath.Join(workingDir, gomodguardFilename)
+
+	if !fileExists(configFile) {
+		if conf.ConfigFile == "" {
+			return nil, nil
+		}
+
+		configFile = conf.ConfigFile
+	}
+
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read gomodguard config file: %s", err)
+	}
+
+	gomodguardConf := &gomodguard.Configuration{}
+
+	err = yaml.Unmarshal(data, gomodguardConf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse gomodguard config file '%s': %s", configFile, err)
+	}
+
+	return gomodguardConf, nil
+}
+
+// getGomodguardViolations returns the reasons the module version is not allowed by gomodguard, the same
+// way gomodguard does when linting a go.mod file.
+func getGomodguardViolations(conf *gomodguard.Configuration, currentModule, module, moduleVersion string) repository.Violations {
+	violations := make(repository.Violations, 0)
+
+	isAllowed := (len(conf.Allowed.Modules) == 0 && len(conf.Allowed.Domains) == 0) ||
+		conf.Allowed.IsAllowedModuleDomain(module) ||
+		conf.Allowed.IsAllowedModule(module)
+
+	blockModuleReason := conf.Blocked.Modules.GetBlockReason(currentModule, module)
+	blockVersionReason := conf.Blocked.Versions.GetBlockReason(module, moduleVersion)
+
+	if !isAllowed && blockModuleReason == nil && blockVersionReason == nil {
+		violations = append(violations, &repository.Violation{
+			Module:  module,
+			Version: moduleVersion,
+			Reason:  "module is not in the allowed modules list",
+		})
+	}
+
+	if blockModuleReason != nil && !blockModuleReason.IsAllowed() {
+		violations = append(violations, &repository.Violation{
+			Module:  module,
+			Version: moduleVersion,
+			Reason:  fmt.Sprintf("module is in the blocked modules list. %s", blockModuleReason.Message()),
+		})
+	}
+
+	if blockVersionReason != nil && !blockVersionReason.IsAllowed() {
+		violations = append(violations, &repository.Violation{
+			Module:  module,
+			Version: moduleVersion,
+			Reason:  blockVersionReason.Message(),
+		})
+	}
+
+	return violations
+}
@@ -0,0 +1,147 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+const testGomodguardConfig = `allowed:
+  modules:
+    - github.com/acme/lib
+    - github.com/acme/homedir
+  domains:
+    - golang.org
+blocked:
+  modules:
+    - github.com/acme/old:
+        recommendations:
+          - github.com/acme/lib
+        reason: "old is no longer maintained"
+  versions:
+    - github.com/acme/homedir:
+        version: "< 1.2.0"
+        reason: "homedir before v1.2.0 panics on windows"
+`
+
+func newGomodguardRepo(t *testing.T) *repository.Repository {
+	dir, err := ioutil.TempDir("", "gomodguard")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	err = ioutil.WriteFile(filepath.Join(dir, ".gomodguard.yaml"), []byte(testGomodguardConfig), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &repository.Repository{Name: "api", SCM: repository.Local, BaseDir: dir}
+}
+
+func TestBumperCheckGomodguardUpdates(t *testing.T) {
+	bumper, err := bump.NewBumper(bump.Configuration{Gomodguard: bump.GomodguardConfig{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := newGomodguardRepo(t)
+
+	var tests = []struct {
+		testName    string
+		module      string
+		newVersion  string
+		wantUpdated bool
+	}{
+		{"should update an allowed module", "github.com/acme/lib", "v1.3.0", true},
+		{"should update a module in an allowed domain", "golang.org/x/mod", "v0.4.0", true},
+		{"should not update a module missing from the allowed list", "github.com/other/lib", "v1.3.0", false},
+		{"should not update a blocked module", "github.com/acme/old", "v2.0.0", false},
+		{"should not update to a blocked version", "github.com/acme/homedir", "v1.1.0", false},
+		{"should update past a blocked version", "github.com/acme/homedir", "v1.2.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			oldVersion, _ := version.Parse("v1.0.0")
+			newVersion, _ := version.Parse(tt.newVersion)
+
+			updates := repository.Updates{{Module: tt.module, OldVersion: oldVersion, NewVersion: newVersion}}
+
+			allowedUpdates, _, err := bumper.CheckGomodguard(repo, "github.com/acme/api", nil, updates)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if updated := len(allowedUpdates) == 1; updated != tt.wantUpdated {
+				t.Errorf("got updated '%v' want '%v'", updated, tt.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestBumperCheckGomodguardViolations(t *testing.T) {
+	bumper, err := bump.NewBumper(bump.Configuration{Gomodguard: bump.GomodguardConfig{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := newGomodguardRepo(t)
+
+	var tests = []struct {
+		testName       string
+		require        string
+		wantViolations int
+	}{
+		{"should not report an allowed module", "github.com/acme/lib@v1.0.0", 0},
+		{"should report a module missing from the allowed list", "github.com/other/lib@v1.0.0", 1},
+		{"should report a blocked module", "github.com/acme/old@v1.0.0", 1},
+		{"should report a blocked version", "github.com/acme/homedir@v1.1.0", 1},
+		{"should not report an allowed version", "github.com/acme/homedir@v1.2.0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, violations, err := bumper.CheckGomodguard(repo, "github.com/acme/api", []string{tt.require}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(violations) != tt.wantViolations {
+				t.Errorf("got %d violations want %d: %v", len(violations), tt.wantViolations, violations)
+			}
+		})
+	}
+}
+
+func TestBumperCheckGomodguardWithoutConfig(t *testing.T) {
+	bumper, err := bump.NewBumper(bump.Configuration{Gomodguard: bump.GomodguardConfig{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "gomodguard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo := &repository.Repository{Name: "api", SCM: repository.Local, BaseDir: dir}
+	updates := repository.Updates{{Module: "github.com/other/lib"}}
+
+	allowedUpdates, violations, err := bumper.CheckGomodguard(repo, "github.com/acme/api", []string{"github.com/other/lib@v1.0.0"}, updates)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(allowedUpdates) != 1 || len(violations) != 0 {
+		t.Errorf("got %d updates and %d violations want the updates unchecked", len(allowedUpdates), len(violations))
+	}
+}
@@ -0,0 +1,325 @@
+package bump
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
+// noToolchain removes the toolchain line from the go.mod file.
+const noToolchain = "none"
+
+// The toolchain of Go 1.21 and later is named after the first release, e.g. go1.21.0, not the language version.
+const firstToolchainReleaseMinor = 21
+
+var errGoVersionValidation = errors.New("build failed with the new Go version")
+
+// GoVersionConfig raises the go directive of the go.mod file to a minimum Go version and manages its
+// toolchain line. Either can be set without the other, nothing is changed when neither are set.
+type GoVersionConfig struct {
+	Minimum       string `yaml:"minimum"`
+	Toolchain     string `yaml:"toolchain"`
+	ValidateBuild bool   `yaml:"validate_build"`
+}
+
+// IsEnabled returns true if a minimum Go version or a toolchain is configured.
+func (c GoVersionConfig) IsEnabled() bool {
+	return c.Minimum != "" || c.Toolchain != ""
+}
+
+// Validate returns an error if the minimum Go version or toolchain are invalid.
+func (c GoVersionConfig) Validate() error {
+	if c.Minimum != "" {
+		_, err := parseGoVersion(c.Minimum)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Toolchain != "" && c.Toolchain != noToolchain {
+		if !strings.HasPrefix(c.Toolchain, "go") {
+			return fmt.Errorf("invalid toolchain '%s', expected a name like go1.22.3 or none", c.Toolchain)
+		}
+
+		_, err := parseGoVersion(strings.TrimPrefix(c.Toolchain, "go"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bumpGoVersion raises the go directive to the minimum Go version and updates the toolchain line. The
+// change is built with the toolchain when validation is enabled and undone if the build fails. Nil is
+// returned if nothing was changed.
+func (b *Bumper) bumpGoVersion(ctx context.Context, repo *repository.Repository, goMod *goModFile) (*repository.GoVersionUpdate, error) {
+	goVersionUpdate, err := getGoVersionUpdate(b.conf.GoVersion, goMod)
+	if err != nil || goVersionUpdate == nil {
+		return nil, err
+	}
+
+	goModFilePath := filepath.Join(repo.ClonePath(), goModFilename)
+	goSumFilePath := filepath.Join(repo.ClonePath(), goSumFilename)
+
+	goModData, err := ioutil.ReadFile(goModFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	goSumData, err := ioutil.ReadFile(goSumFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"mod", "edit", fmt.Sprintf("-go=%s", goVersionUpdate.NewGo)}
+
+	if goVersionUpdate.NewToolchain != goVersionUpdate.OldToolchain {
+		toolchain := goVersionUpdate.NewToolchain
+		if toolchain == "" {
+			toolchain = noToolchain
+		}
+
+		args = append(args, fmt.Sprintf("-toolchain=%s", toolchain))
+	}
+
+	err = runGoCommandWithEnv(ctx, repo.ClonePath(), nil, args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update the go directive: %s", err)
+	}
+
+	if !b.conf.GoVersion.ValidateBuild {
+		return goVersionUpdate, nil
+	}
+
+	err = runGoModTidy(ctx, repo.ClonePath())
+	if err == nil {
+		toolchainEnv := fmt.Sprintf("GOTOOLCHAIN=%s", getValidationToolchain(goVersionUpdate))
+
+		err = runGoCommandWithEnv(ctx, repo.ClonePath(), []string{toolchainEnv}, "build", "./...")
+	}
+
+	if err != nil {
+		// Restore the go.mod and go.sum files so the module updates can still be proposed.
+		errRestore := ioutil.WriteFile(goModFilePath, goModData, 0644) // nolint: gosec
+		if errRestore != nil {
+			return nil, errRestore
+		}
+
+		errRestore = ioutil.WriteFile(goSumFilePath, goSumData, 0644) // nolint: gosec
+		if errRestore != nil {
+			return nil, errRestore
+		}
+
+		return nil, fmt.Errorf("%w: %s", errGoVersionValidation, err)
+	}
+
+	return goVersionUpdate, nil
+}
+
+// getGoVersionUpdate returns the new go directive and toolchain line. The go directive is only raised and
+// the toolchain line is only raised or removed. Nil is returned if neither need to change.
+func getGoVersionUpdate(conf GoVersionConfig, goMod *goModFile) (*repository.GoVersionUpdate, error) {
+	goVersionUpdate := &repository.GoVersionUpdate{
+		OldGo:        goMod.Go,
+		NewGo:        goMod.Go,
+		OldToolchain: goMod.Toolchain,
+		NewToolchain: goMod.Toolchain,
+	}
+
+	isLower, err := isGoVersionLower(goMod.Go, conf.Minimum)
+	if err != nil {
+		return nil, err
+	}
+
+	if isLower {
+		goVersionUpdate.NewGo = conf.Minimum
+	}
+
+	switch {
+	case conf.Toolchain == noToolchain:
+		goVersionUpdate.NewToolchain = ""
+	case conf.Toolchain != "":
+		isLower, err = isGoVersionLower(strings.TrimPrefix(goMod.Toolchain, "go"), strings.TrimPrefix(conf.Toolchain, "go"))
+		if err != nil {
+			return nil, err
+		}
+
+		if isLower {
+			goVersionUpdate.NewToolchain = conf.Toolchain
+		}
+	}
+
+	// A toolchain line lower than the go directive has no effect.
+	if goVersionUpdate.NewToolchain != "" {
+		isLower, err = isGoVersionLower(strings.TrimPrefix(goVersionUpdate.NewToolchain, "go"), goVersionUpdate.NewGo)
+		if err != nil {
+			return nil, err
+		}
+
+		if isLower {
+			goVersionUpdate.NewToolchain = ""
+		}
+	}
+
+	if goVersionUpdate.NewGo == goVersionUpdate.OldGo && goVersionUpdate.NewToolchain == goVersionUpdate.OldToolchain {
+		return nil, nil
+	}
+
+	return goVersionUpdate, nil
+}
+
+// getValidationToolchain returns the toolchain to build with, the toolchain line or the go directive's release.
+func getValidationToolchain(goVersionUpdate *repository.GoVersionUpdate) string {
+	if goVersionUpdate.NewToolchain != "" {
+		return goVersionUpdate.NewToolchain
+	}
+
+	goVersion, err := parseGoVersion(goVersionUpdate.NewGo)
+	if err == nil && goVersion.isLanguageVersion() && goVersion.minor >= firstToolchainReleaseMinor {
+		return fmt.Sprintf("go%s.0", goVersionUpdate.NewGo)
+	}
+
+	return fmt.Sprintf("go%s", goVersionUpdate.NewGo)
+}
+
+// runGoCommandWithEnv runs the go command in the working directory with the extra environment variables.
+func runGoCommandWithEnv(ctx context.Context, workingDir string, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "go", args...)
+
+	cmd.Dir = workingDir
+
+	cmd.Env = append(os.Environ(), env...)
+
+	stderr := new(bytes.Buffer)
+
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%s: %s", strings.TrimSpace(stderr.String()), err)
+	}
+
+	return nil
+}
+
+// goVersion is a Go release or language version e.g. 1.21, 1.21rc1 or 1.21.3.
+type goVersion struct {
+	major      int
+	minor      int
+	patch      int
+	prerelease string
+	number     int
+}
+
+// isLanguageVersion returns true if the version has no patch or prerelease e.g. 1.21.
+func (v goVersion) isLanguageVersion() bool {
+	return v.patch == -1 && v.prerelease == ""
+}
+
+// rank orders the versions with the same major and minor, 1.21 < 1.21beta1 < 1.21rc1 < 1.21.0.
+func (v goVersion) rank() int {
+	switch {
+	case v.isLanguageVersion():
+		return 0
+	case v.prerelease == "beta":
+		return 1
+	case v.prerelease == "rc":
+		return 2 // nolint: gomnd
+	default:
+		return 3 // nolint: gomnd
+	}
+}
+
+func parseGoVersion(s string) (goVersion, error) {
+	invalidErr := fmt.Errorf("invalid Go version '%s', expected a version like 1.21 or 1.21.3", s)
+
+	v := goVersion{patch: -1}
+
+	for _, prerelease := range []string{"beta", "rc"} {
+		if n := strings.Index(s, prerelease); n > 0 {
+			number, err := strconv.Atoi(s[n+len(prerelease):])
+			if err != nil {
+				return v, invalidErr
+			}
+
+			v.prerelease = prerelease
+			v.number = number
+			s = s[:n]
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && v.prerelease != "") { // nolint: gomnd
+		return v, invalidErr
+	}
+
+	numbers := make([]int, len(parts))
+
+	for n := range parts {
+		number, err := strconv.Atoi(parts[n])
+		if err != nil || number < 0 {
+			return v, invalidErr
+		}
+
+		numbers[n] = number
+	}
+
+	v.major = numbers[0]
+	v.minor = numbers[1]
+
+	if len(numbers) == 3 { // nolint: gomnd
+		v.patch = numbers[2]
+	}
+
+	return v, nil
+}
+
+// isGoVersionLower returns true if the version is lower than the other version. An empty version is lower
+// than any version and nothing is lower than an empty version.
+func isGoVersionLower(s, other string) (bool, error) {
+	if other == "" {
+		return false, nil
+	}
+
+	if s == "" {
+		return true, nil
+	}
+
+	v, err := parseGoVersion(s)
+	if err != nil {
+		return false, err
+	}
+
+	o, err := parseGoVersion(other)
+	if err != nil {
+		return false, err
+	}
+
+	if v.major != o.major {
+		return v.major < o.major, nil
+	}
+
+	if v.minor != o.minor {
+		return v.minor < o.minor, nil
+	}
+
+	if v.rank() != o.rank() {
+		return v.rank() < o.rank(), nil
+	}
+
+	if v.prerelease != "" {
+		return v.number < o.number, nil
+	}
+
+	return v.patch < o.patch, nil
+}
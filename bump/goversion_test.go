@@ -0,0 +1,145 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+func TestGoVersionConfigValidate(t *testing.T) {
+	var tests = []struct {
+		testName string
+		config   bump.GoVersionConfig
+		wantErr  bool
+	}{
+		{"should be valid when not set", bump.GoVersionConfig{}, false},
+		{"should be valid with a language version", bump.GoVersionConfig{Minimum: "1.21"}, false},
+		{"should be valid with a release", bump.GoVersionConfig{Minimum: "1.21.3", Toolchain: "go1.22.0"}, false},
+		{"should be valid with a release candidate", bump.GoVersionConfig{Minimum: "1.22rc1"}, false},
+		{"should be valid when removing the toolchain", bump.GoVersionConfig{Minimum: "1.21", Toolchain: "none"}, false},
+		{"should be invalid with only a major version", bump.GoVersionConfig{Minimum: "1"}, true},
+		{"should be invalid with a v prefix", bump.GoVersionConfig{Minimum: "v1.21.0"}, true},
+		{"should be invalid with a release candidate patch", bump.GoVersionConfig{Minimum: "1.21.0rc1"}, true},
+		{"should be invalid with a toolchain without the go prefix", bump.GoVersionConfig{Toolchain: "1.22.0"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error '%v' want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsGoVersionLower(t *testing.T) {
+	var tests = []struct {
+		testName  string
+		version   string
+		other     string
+		wantLower bool
+		wantErr   bool
+	}{
+		{"should be lower with a lower minor", "1.20", "1.21", true, false},
+		{"should not be lower with a higher minor", "1.22", "1.21", false, false},
+		{"should not be lower than itself", "1.21", "1.21", false, false},
+		{"should be lower with a lower major", "1.21", "2.0", true, false},
+		{"should order minors numerically", "1.9", "1.10", true, false},
+		{"should be lower with a lower patch", "1.21.2", "1.21.3", true, false},
+		{"should order the language version before its beta", "1.21", "1.21beta1", true, false},
+		{"should order a beta before a release candidate", "1.21beta2", "1.21rc1", true, false},
+		{"should order release candidates", "1.21rc1", "1.21rc2", true, false},
+		{"should order a release candidate before the first release", "1.21rc2", "1.21.0", true, false},
+		{"should not be lower than a language version with a release", "1.21.0", "1.21", false, false},
+		{"should be lower when empty", "", "1.21", true, false},
+		{"should not be lower than an empty version", "1.21", "", false, false},
+		{"should fail with an invalid version", "1.21.x", "1.22", false, true},
+		{"should fail with an invalid other version", "1.21", "go1.22", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			lower, err := bump.IsGoVersionLower(tt.version, tt.other)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error '%v' want error %v", err, tt.wantErr)
+			}
+
+			if lower != tt.wantLower {
+				t.Errorf("got '%v' want '%v'", lower, tt.wantLower)
+			}
+		})
+	}
+}
+
+func TestGetGoVersionUpdate(t *testing.T) {
+	var tests = []struct {
+		testName      string
+		config        bump.GoVersionConfig
+		goDirective   string
+		toolchain     string
+		wantNil       bool
+		wantGo        string
+		wantToolchain string
+	}{
+		{"should raise the go directive", bump.GoVersionConfig{Minimum: "1.21"}, "1.19", "", false, "1.21", ""},
+		{"should not lower the go directive", bump.GoVersionConfig{Minimum: "1.21"}, "1.22", "", true, "", ""},
+		{"should not change the go directive at the minimum", bump.GoVersionConfig{Minimum: "1.21"}, "1.21", "", true, "", ""},
+		{"should keep the toolchain line without a toolchain", bump.GoVersionConfig{Minimum: "1.21"}, "1.20", "go1.22.3", false, "1.21", "go1.22.3"},
+		{"should raise the toolchain line", bump.GoVersionConfig{Minimum: "1.21", Toolchain: "go1.22.3"}, "1.21", "go1.21.5", false, "1.21", "go1.22.3"},
+		{"should add the toolchain line", bump.GoVersionConfig{Minimum: "1.21", Toolchain: "go1.22.3"}, "1.21", "", false, "1.21", "go1.22.3"},
+		{"should not lower the toolchain line", bump.GoVersionConfig{Minimum: "1.21", Toolchain: "go1.22.3"}, "1.21", "go1.23.0", true, "", ""},
+		{"should remove the toolchain line", bump.GoVersionConfig{Minimum: "1.21", Toolchain: "none"}, "1.21", "go1.22.3", false, "1.21", ""},
+		{"should remove a toolchain line lower than the new go directive", bump.GoVersionConfig{Minimum: "1.22.0"}, "1.21", "go1.21.5", false, "1.22.0", ""},
+		{"should raise only the toolchain line without a minimum", bump.GoVersionConfig{Toolchain: "go1.22.3"}, "1.21", "go1.21.5", false, "1.21", "go1.22.3"},
+		{"should remove only the toolchain line without a minimum", bump.GoVersionConfig{Toolchain: "none"}, "1.21", "go1.22.3", false, "1.21", ""},
+		{"should not change a go.mod file without a toolchain line to remove", bump.GoVersionConfig{Toolchain: "none"}, "1.21", "", true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			goVersionUpdate, err := bump.GetGoVersionUpdate(tt.config, tt.goDirective, tt.toolchain)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if tt.wantNil {
+				if goVersionUpdate != nil {
+					t.Errorf("got update %+v want nil", goVersionUpdate)
+				}
+
+				return
+			}
+
+			if goVersionUpdate == nil {
+				t.Fatal("got nil want an update")
+			}
+
+			if goVersionUpdate.NewGo != tt.wantGo || goVersionUpdate.NewToolchain != tt.wantToolchain {
+				t.Errorf("got go '%s' toolchain '%s' want go '%s' toolchain '%s'", goVersionUpdate.NewGo, goVersionUpdate.NewToolchain, tt.wantGo, tt.wantToolchain)
+			}
+		})
+	}
+}
+
+func TestGoVersionConfigIsEnabled(t *testing.T) {
+	var tests = []struct {
+		testName    string
+		config      bump.GoVersionConfig
+		wantEnabled bool
+	}{
+		{"should be disabled when not set", bump.GoVersionConfig{}, false},
+		{"should be enabled with a minimum", bump.GoVersionConfig{Minimum: "1.21"}, true},
+		{"should be enabled with only a toolchain", bump.GoVersionConfig{Toolchain: "go1.22.3"}, true},
+		{"should be enabled when only removing the toolchain", bump.GoVersionConfig{Toolchain: "none"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			if enabled := tt.config.IsEnabled(); enabled != tt.wantEnabled {
+				t.Errorf("got '%v' want '%v'", enabled, tt.wantEnabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package bump
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultHookTimeout = 10 * time.Minute
+
+// HookConfig is a command that is run in the repository after its modules are updated, e.g. to regenerate
+// code with `go generate ./...`. The tracked files the command changes are committed with the go.mod file,
+// new files are only committed when they match one of the paths.
+type HookConfig struct {
+	Name    string        `yaml:"name"`
+	Command []string      `yaml:"command"`
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Paths are the patterns of the new files to commit relative to the repository, e.g. mocks/*.go, in the
+	// syntax of filepath.Match.
+	Paths []string `yaml:"paths"`
+}
+
+// GetName returns the name of the hook or the command if it does not have one.
+func (c HookConfig) GetName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	return strings.Join(c.Command, " ")
+}
+
+// Validate returns an error if the hook does not have a command.
+func (c HookConfig) Validate() error {
+	if len(c.Command) == 0 {
+		return fmt.Errorf("hook '%s' has no command", c.Name)
+	}
+
+	for _, pattern := range c.Paths {
+		_, err := filepath.Match(pattern, "")
+		if err != nil || filepath.IsAbs(pattern) || strings.HasPrefix(filepath.Clean(pattern), "..") {
+			return fmt.Errorf("hook '%s' has invalid path '%s', expected a pattern relative to the repository", c.GetName(), pattern)
+		}
+	}
+
+	return nil
+}
+
+// runHook runs the hook command in the working directory, it is killed when the timeout is reached or the
+// context is cancelled.
+func runHook(ctx context.Context, workingDir string, hook HookConfig) error {
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = defaultHookTimeout
+	}
+
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(hookCtx, hook.Command[0], hook.Command[1:]...) // nolint: gosec
+
+	cmd.Dir = workingDir
+
+	cmd.Env = os.Environ()
+
+	output := new(bytes.Buffer)
+
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return fmt.Errorf("hook '%s' was cancelled: %w", hook.GetName(), ctx.Err())
+	}
+
+	if hookCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("hook '%s' timed out after %v", hook.GetName(), timeout)
+	}
+
+	if err != nil {
+		return fmt.Errorf("hook '%s' failed: %s: %s", hook.GetName(), strings.TrimSpace(output.String()), err)
+	}
+
+	return nil
+}
+
+// getNewFiles returns the files matching the paths of the hooks and the go.sum file, relative to the working
+// directory. These are committed even if they are not tracked yet.
+func getNewFiles(workingDir string, hooks []HookConfig) ([]string, error) {
+	newFiles := make([]string, 0)
+
+	if fileExists(filepath.Join(workingDir, goSumFilename)) {
+		newFiles = append(newFiles, goSumFilename)
+	}
+
+	for _, hook := range hooks {
+		for _, pattern := range hook.Paths {
+			matches, err := filepath.Glob(filepath.Join(workingDir, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("hook '%s' has invalid path '%s': %s", hook.GetName(), pattern, err)
+			}
+
+			for _, match := range matches {
+				if !fileExists(match) {
+					continue
+				}
+
+				newFile, err := filepath.Rel(workingDir, match)
+				if err != nil {
+					return nil, err
+				}
+
+				newFiles = append(newFiles, filepath.ToSlash(newFile))
+			}
+		}
+	}
+
+	return newFiles, nil
+}
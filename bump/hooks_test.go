@@ -0,0 +1,32 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+func TestHookConfigValidate(t *testing.T) {
+	var tests = []struct {
+		testName string
+		config   bump.HookConfig
+		wantErr  bool
+	}{
+		{"should be valid with a command", bump.HookConfig{Command: []string{"go", "generate", "./..."}}, false},
+		{"should be valid with paths", bump.HookConfig{Command: []string{"mockery"}, Paths: []string{"mocks/*.go", "docs/api.md"}}, false},
+		{"should be invalid without a command", bump.HookConfig{Name: "generate"}, true},
+		{"should be invalid with a malformed pattern", bump.HookConfig{Command: []string{"mockery"}, Paths: []string{"mocks/[.go"}}, true},
+		{"should be invalid with an absolute path", bump.HookConfig{Command: []string{"mockery"}, Paths: []string{"/tmp/*.go"}}, true},
+		{"should be invalid with a path outside the repository", bump.HookConfig{Command: []string{"mockery"}, Paths: []string{"../mocks/*.go"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error '%v' want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package bump
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+const ignoreUntilFormat = "2006-01-02"
+
+// IgnoreConfig ignores versions of a module, the newest version that is not ignored is used instead. Versions
+// are exact versions or semantic version ranges. The versions are no longer ignored from the Until date.
+type IgnoreConfig struct {
+	Module   string   `yaml:"module"`
+	Versions []string `yaml:"versions"`
+	Until    string   `yaml:"until"`
+	Reason   string   `yaml:"reason"`
+}
+
+// Validate returns an error if the module pattern, versions or until date are invalid.
+func (c IgnoreConfig) Validate() error {
+	err := validateModulePattern(c.Module)
+	if err != nil {
+		return err
+	}
+
+	if len(c.Versions) == 0 {
+		return fmt.Errorf("ignore for module '%s' has no versions", c.Module)
+	}
+
+	for _, ignoreVersion := range c.Versions {
+		if _, err := version.Parse(ignoreVersion); err == nil {
+			continue
+		}
+
+		_, err := semver.NewConstraint(ignoreVersion)
+		if err != nil {
+			return fmt.Errorf("invalid ignored version '%s' for module '%s': %s", ignoreVersion, c.Module, err)
+		}
+	}
+
+	if c.Until != "" {
+		_, err := time.Parse(ignoreUntilFormat, c.Until)
+		if err != nil {
+			return fmt.Errorf("invalid ignore until date '%s' for module '%s', expected YYYY-MM-DD", c.Until, c.Module)
+		}
+	}
+
+	return nil
+}
+
+// IsExpired returns true if the until date has been reached.
+func (c IgnoreConfig) IsExpired(now time.Time) bool {
+	if c.Until == "" {
+		return false
+	}
+
+	until, err := time.Parse(ignoreUntilFormat, c.Until)
+	if err != nil {
+		return false
+	}
+
+	return !now.Before(until)
+}
+
+// IsIgnored returns true if the module version is ignored and the ignore has not expired.
+func (c IgnoreConfig) IsIgnored(module string, moduleVersion *version.Version, now time.Time) bool {
+	if !c.appliesTo(module, now) {
+		return false
+	}
+
+	for _, ignoreVersion := range c.Versions {
+		if exactVersion, err := version.Parse(ignoreVersion); err == nil {
+			if exactVersion.Equal(moduleVersion) {
+				return true
+			}
+
+			continue
+		}
+
+		constraint, err := semver.NewConstraint(ignoreVersion)
+		if err != nil {
+			continue
+		}
+
+		semverVersion, err := semver.NewVersion(moduleVersion.String())
+		if err == nil && constraint.Check(semverVersion) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c IgnoreConfig) appliesTo(module string, now time.Time) bool {
+	return matchModulePattern(c.Module, module) && !c.IsExpired(now)
+}
+
+// isVersionIgnored returns true if any of the ignores ignore the module version.
+func (c Configuration) isVersionIgnored(module string, moduleVersion *version.Version, now time.Time) bool {
+	for _, ignore := range c.Ignore {
+		if ignore.IsIgnored(module, moduleVersion, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasIgnoredVersions returns true if any of the ignores that have not expired apply to the module.
+func (c Configuration) hasIgnoredVersions(module string, now time.Time) bool {
+	for _, ignore := range c.Ignore {
+		if ignore.appliesTo(module, now) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+func TestIgnoreConfigIsIgnored(t *testing.T) {
+	now := time.Date(2020, time.April, 15, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		testName    string
+		ignore      bump.IgnoreConfig
+		module      string
+		version     string
+		wantIgnored bool
+	}{
+		{"should ignore an exact version", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"v1.5.3"}}, "github.com/acme/lib", "v1.5.3", true},
+		{"should ignore an exact version without a v prefix", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"1.5.3"}}, "github.com/acme/lib", "v1.5.3", true},
+		{"should not ignore another version", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"v1.5.3"}}, "github.com/acme/lib", "v1.5.4", false},
+		{"should ignore a version in a range", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{">= 1.6.0, < 1.7.0"}}, "github.com/acme/lib", "v1.6.2", true},
+		{"should not ignore a version outside of a range", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{">= 1.6.0, < 1.7.0"}}, "github.com/acme/lib", "v1.7.0", false},
+		{"should not ignore another module", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"v1.5.3"}}, "github.com/acme/other", "v1.5.3", false},
+		{"should ignore a module matching a glob", bump.IgnoreConfig{Module: "github.com/acme/*", Versions: []string{"v1.5.3"}}, "github.com/acme/other", "v1.5.3", true},
+		{"should ignore before the until date", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"v1.5.3"}, Until: "2020-04-16"}, "github.com/acme/lib", "v1.5.3", true},
+		{"should not ignore from the until date", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"v1.5.3"}, Until: "2020-04-15"}, "github.com/acme/lib", "v1.5.3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			moduleVersion, err := version.Parse(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ignored := tt.ignore.IsIgnored(tt.module, moduleVersion, now)
+			if ignored != tt.wantIgnored {
+				t.Errorf("got '%v' want '%v'", ignored, tt.wantIgnored)
+			}
+		})
+	}
+}
+
+func TestIgnoreConfigValidate(t *testing.T) {
+	var tests = []struct {
+		testName string
+		ignore   bump.IgnoreConfig
+		wantErr  bool
+	}{
+		{"should be valid", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"v1.5.3", "~1.6"}, Until: "2020-04-16"}, false},
+		{"should be invalid without versions", bump.IgnoreConfig{Module: "github.com/acme/lib"}, true},
+		{"should be invalid with a bad range", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{">= one"}}, true},
+		{"should be invalid with a bad until date", bump.IgnoreConfig{Module: "github.com/acme/lib", Versions: []string{"v1.5.3"}, Until: "16/04/2020"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.ignore.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error '%v' want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
+func TestModuleFilterIsModuleAllowed(t *testing.T) {
+	var tests = []struct {
+		testName    string
+		filter      bump.ModuleFilter
+		module      string
+		wantAllowed bool
+	}{
+		{"should allow any module without lists", bump.ModuleFilter{}, "github.com/acme/lib", true},
+		{"should block an exact module", bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib"}}, "github.com/acme/lib", false},
+		{"should not block a module with the same prefix", bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib"}}, "github.com/acme/library", true},
+		{"should block a module in a domain", bump.ModuleFilter{BlockedDomains: []string{"github.com/acme"}}, "github.com/acme/lib", false},
+		{"should block a domain with a trailing slash", bump.ModuleFilter{BlockedDomains: []string{"github.com/acme/"}}, "github.com/acme/lib", false},
+		{"should not block a domain with the same prefix", bump.ModuleFilter{BlockedDomains: []string{"github.com/acme"}}, "github.com/acmecorp/lib", true},
+		{"should block a single segment glob", bump.ModuleFilter{BlockedModules: []string{"github.com/acme/*"}}, "github.com/acme/lib", false},
+		{"should not block nested modules with a single segment glob", bump.ModuleFilter{BlockedModules: []string{"github.com/acme/*"}}, "github.com/acme/lib/v2", true},
+		{"should block nested modules with a multi segment glob", bump.ModuleFilter{BlockedModules: []string{"github.com/acme/**"}}, "github.com/acme/lib/v2", false},
+		{"should block a glob in the middle of a path", bump.ModuleFilter{BlockedModules: []string{"github.com/*/lib"}}, "github.com/acme/lib", false},
+		{"should not block another organization with a glob", bump.ModuleFilter{BlockedModules: []string{"github.com/acme/**"}}, "github.com/acmecorp/lib", true},
+		{"should block a regex", bump.ModuleFilter{BlockedModules: []string{`regex:github\.com/acme/lib(/v\d+)?`}}, "github.com/acme/lib/v3", false},
+		{"should anchor a regex", bump.ModuleFilter{BlockedModules: []string{`regex:acme/lib`}}, "github.com/acme/lib", true},
+		{"should not block the module with a version block", bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib@v1.5.3"}}, "github.com/acme/lib", true},
+		{"should allow an allowed module before a blocked domain", bump.ModuleFilter{AllowedModules: []string{"github.com/acme/lib"}, BlockedDomains: []string{"github.com/acme"}}, "github.com/acme/lib", true},
+		{"should allow an allowed glob before a blocked module", bump.ModuleFilter{AllowedModules: []string{"github.com/acme/**"}, BlockedModules: []string{"github.com/acme/lib"}}, "github.com/acme/lib", true},
+		{"should block a module not in the allow list", bump.ModuleFilter{AllowedModules: []string{"github.com/acme/**"}}, "github.com/other/lib", false},
+		{"should allow a module in an allowed domain", bump.ModuleFilter{AllowedDomains: []string{"github.com/acme"}}, "github.com/acme/lib", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			allowed := tt.filter.IsModuleAllowed(tt.module)
+			if allowed != tt.wantAllowed {
+				t.Errorf("got '%v' want '%v'", allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestModuleFilterIsVersionAllowed(t *testing.T) {
+	filter := bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib@v1.5.3", "github.com/other/**@v2.0.0"}}
+
+	var tests = []struct {
+		testName    string
+		module      string
+		version     string
+		wantAllowed bool
+	}{
+		{"should block the version", "github.com/acme/lib", "v1.5.3", false},
+		{"should allow another version", "github.com/acme/lib", "v1.5.4", true},
+		{"should allow the version of another module", "github.com/acme/other", "v1.5.3", true},
+		{"should block the version of a glob", "github.com/other/lib", "v2.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			allowed := filter.IsVersionAllowed(tt.module, tt.version)
+			if allowed != tt.wantAllowed {
+				t.Errorf("got '%v' want '%v'", allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestModuleFilterValidate(t *testing.T) {
+	var tests = []struct {
+		testName string
+		filter   bump.ModuleFilter
+		wantErr  bool
+	}{
+		{"should be valid without lists", bump.ModuleFilter{}, false},
+		{"should be valid with patterns", bump.ModuleFilter{AllowedModules: []string{"github.com/acme/**", `regex:github\.com/.*`}, BlockedModules: []string{"github.com/acme/lib@v1.5.3"}}, false},
+		{"should be invalid with a bad regex", bump.ModuleFilter{BlockedModules: []string{"regex:github.com/(acme"}}, true},
+		{"should be invalid with a bad glob", bump.ModuleFilter{BlockedModules: []string{"github.com/[acme"}}, true},
+		{"should be invalid with an allowed version", bump.ModuleFilter{AllowedModules: []string{"github.com/acme/lib@v1.5.3"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error '%v' want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigurationIsVersionAllowed(t *testing.T) {
+	conf := bump.Configuration{
+		ModuleFilter:     bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib@v1.5.3"}},
+		Indirect:         bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib@v1.6.0"}},
+		Tools:            bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib@v1.7.0"}},
+		RepositoryFilter: bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib@v1.8.0"}},
+	}
+
+	var tests = []struct {
+		testName    string
+		kind        repository.UpdateKind
+		version     string
+		wantAllowed bool
+	}{
+		{"should block a direct update to a version blocked for direct updates", repository.DirectUpdate, "v1.5.3", false},
+		{"should not block a direct update to a version blocked for indirect updates", repository.DirectUpdate, "v1.6.0", true},
+		{"should not block a direct update to a version blocked for tools", repository.DirectUpdate, "v1.7.0", true},
+		{"should block an indirect update to a version blocked for indirect updates", repository.IndirectUpdate, "v1.6.0", false},
+		{"should not block an indirect update to a version blocked for direct updates", repository.IndirectUpdate, "v1.5.3", true},
+		{"should block a tool update to a version blocked for tools", repository.ToolUpdate, "v1.7.0", false},
+		{"should not block a tool update to a version blocked for direct updates", repository.ToolUpdate, "v1.5.3", true},
+		{"should block any update to a version blocked by the repository", repository.IndirectUpdate, "v1.8.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			allowed := conf.IsVersionAllowed(tt.kind, "github.com/acme/lib", tt.version)
+			if allowed != tt.wantAllowed {
+				t.Errorf("got '%v' want '%v'", allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestConfigurationTarget(t *testing.T) {
+	conf := bump.Configuration{
+		RepositoryFilter: bump.ModuleFilter{BlockedModules: []string{"github.com/acme/blocked"}},
+		Tools:            bump.ModuleFilter{BlockedDomains: []string{"tools.acme.com"}},
+		GoVersion:        bump.GoVersionConfig{Minimum: "1.21"},
+		Replace:          bump.ReplaceConfig{Update: true},
+	}
+
+	conf.Vulnerabilities.Dir = "vulndb"
+
+	var tests = []struct {
+		testName    string
+		target      string
+		module      string
+		wantAllowed bool
+	}{
+		{"should allow the targeted module", "github.com/acme/lib", "github.com/acme/lib", true},
+		{"should not allow other modules", "github.com/acme/lib", "github.com/acme/other", false},
+		{"should not allow a targeted module that is blocked", "github.com/acme/blocked", "github.com/acme/blocked", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			targeted := conf.Target(tt.target)
+
+			for _, allowed := range []bool{targeted.IsModuleAllowed(tt.module), targeted.IsIndirectModuleAllowed(tt.module), targeted.IsToolModuleAllowed(tt.module)} {
+				if allowed != tt.wantAllowed {
+					t.Errorf("got '%v' want '%v'", allowed, tt.wantAllowed)
+				}
+			}
+
+			if targeted.GoVersion.IsEnabled() || targeted.Vulnerabilities.IsEnabled() || targeted.Replace.Update {
+				t.Errorf("got go version, vulnerability and replace updates enabled '%v' '%v' '%v' want them disabled", targeted.GoVersion.IsEnabled(), targeted.Vulnerabilities.IsEnabled(), targeted.Replace.Update)
+			}
+		})
+	}
+
+	if !conf.IsModuleAllowed("github.com/acme/other") || !conf.Vulnerabilities.IsEnabled() {
+		t.Errorf("got the configuration changed by Target want it unchanged")
+	}
+
+	if conf.Target("tools.acme.com/gen").IsToolModuleAllowed("tools.acme.com/gen") {
+		t.Errorf("got a targeted tool blocked by the tools lists allowed want it blocked")
+	}
+}
+
+func TestConfigurationValidatePatterns(t *testing.T) {
+	var tests = []struct {
+		testName string
+		config   bump.Configuration
+		wantErr  bool
+	}{
+		{"should be valid with group and prerelease patterns", bump.Configuration{Groups: []bump.GroupConfig{{Name: "aws", Modules: []string{`regex:github\.com/aws/.*`}}}, Prereleases: []bump.PrereleaseConfig{{Modules: []string{"github.com/acme/**"}}}}, false},
+		{"should be invalid with a bad group regex", bump.Configuration{Groups: []bump.GroupConfig{{Name: "aws", Modules: []string{"regex:github.com/(aws"}}}}, true},
+		{"should be invalid with a bad prerelease glob", bump.Configuration{Prereleases: []bump.PrereleaseConfig{{Modules: []string{"github.com/[acme"}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error '%v' want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompileRegexPatternOnce(t *testing.T) {
+	first, err := bump.CompileRegexPattern(`regex:github\.com/acme/(lib|api)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second, err := bump.CompileRegexPattern(`regex:github\.com/acme/(lib|api)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if first != second {
+		t.Error("got the pattern compiled twice want it compiled once")
+	}
+}
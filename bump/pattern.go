@@ -0,0 +1,118 @@
+package bump
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// regexPatternPrefix marks a module pattern as a regular expression matched against the whole module path.
+const regexPatternPrefix = "regex:"
+
+// globSegmentWildcard matches any number of path segments in a module glob.
+const globSegmentWildcard = "**"
+
+// splitModulePattern returns the module pattern and the version of an entry like github.com/acme/lib@v1.5.3.
+// The version is empty if the entry applies to all versions.
+func splitModulePattern(entry string) (string, string) {
+	if strings.HasPrefix(entry, regexPatternPrefix) {
+		return entry, ""
+	}
+
+	n := strings.LastIndex(entry, "@")
+	if n < 0 {
+		return entry, ""
+	}
+
+	return entry[:n], entry[n+1:]
+}
+
+// validateModulePattern returns an error if the regex or glob module pattern cannot be compiled.
+func validateModulePattern(pattern string) error {
+	if strings.HasPrefix(pattern, regexPatternPrefix) {
+		_, err := compileRegexPattern(pattern)
+		return err
+	}
+
+	for _, segment := range strings.Split(pattern, "/") {
+		_, err := path.Match(segment, "")
+		if err != nil {
+			return fmt.Errorf("invalid module pattern '%s': %s", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// matchModulePattern returns true if the module matches the pattern. Patterns are an exact module path, a
+// regex prefixed with 'regex:' or a glob where '*' matches within a path segment and '**' any number of segments.
+func matchModulePattern(pattern, module string) bool {
+	if strings.HasPrefix(pattern, regexPatternPrefix) {
+		regex, err := compileRegexPattern(pattern)
+		return err == nil && regex.MatchString(module)
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return pattern == module
+	}
+
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(module, "/"))
+}
+
+func matchGlobSegments(patterns, segments []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+
+	if patterns[0] == globSegmentWildcard {
+		for n := 0; n <= len(segments); n++ {
+			if matchGlobSegments(patterns[1:], segments[n:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	matched, err := path.Match(patterns[0], segments[0])
+	if err != nil || !matched {
+		return false
+	}
+
+	return matchGlobSegments(patterns[1:], segments[1:])
+}
+
+// matchDomain returns true if the module is the domain or in it, the domain github.com/acme matches
+// github.com/acme/lib but not github.com/acmecorp/lib.
+func matchDomain(domain, module string) bool {
+	domain = strings.TrimSuffix(domain, "/")
+
+	return module == domain || strings.HasPrefix(module, domain+"/")
+}
+
+// regexPatterns are the compiled regex module patterns by pattern, they are compiled when the configuration is
+// validated and shared by the bumps running concurrently.
+var regexPatterns sync.Map
+
+// compileRegexPattern compiles the regex anchored to match the whole module path, or returns it if it was already
+// compiled.
+func compileRegexPattern(pattern string) (*regexp.Regexp, error) {
+	if regex, ok := regexPatterns.Load(pattern); ok {
+		return regex.(*regexp.Regexp), nil
+	}
+
+	regex, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", strings.TrimPrefix(pattern, regexPatternPrefix)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid module pattern '%s': %s", pattern, err)
+	}
+
+	regexPatterns.Store(pattern, regex)
+
+	return regex, nil
+}
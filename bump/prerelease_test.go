@@ -0,0 +1,174 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+func TestConfigurationGetAllowedPrereleases(t *testing.T) {
+	conf := bump.Configuration{Prereleases: []bump.PrereleaseConfig{
+		{Domains: []string{"github.com/acme"}, AllowPrerelease: []string{"rc"}},
+		{Modules: []string{"github.com/acme/lib"}, AllowPrerelease: []string{"alpha", "beta"}},
+	}}
+
+	var tests = []struct {
+		testName     string
+		module       string
+		wantChannels []string
+	}{
+		{"should use the module before the domain", "github.com/acme/lib", []string{"alpha", "beta"}},
+		{"should use the domain", "github.com/acme/api", []string{"rc"}},
+		{"should allow no channels for another module", "github.com/other/lib", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			channels := conf.GetAllowedPrereleases(tt.module)
+			if !reflect.DeepEqual(channels, tt.wantChannels) {
+				t.Errorf("got '%v' want '%v'", channels, tt.wantChannels)
+			}
+		})
+	}
+}
+
+func TestIsPrereleaseInChannels(t *testing.T) {
+	var tests = []struct {
+		testName  string
+		version   string
+		channels  []string
+		wantMatch bool
+	}{
+		{"should match a numbered identifier", "v1.2.0-rc.1", []string{"rc"}, true},
+		{"should match a number attached to the channel", "v1.2.0-rc1", []string{"rc"}, true},
+		{"should match the channel in any case", "v1.2.0-RC.1", []string{"rc"}, true},
+		{"should match one of the channels", "v1.2.0-beta.2", []string{"alpha", "beta"}, true},
+		{"should not match another channel", "v1.2.0-alpha.1", []string{"rc"}, false},
+		{"should not match a channel with the same prefix", "v1.2.0-rcx.1", []string{"rc"}, false},
+		{"should not match without channels", "v1.2.0-rc.1", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			match := bump.IsPrereleaseInChannels(mustParseVersion(t, tt.version), tt.channels)
+			if match != tt.wantMatch {
+				t.Errorf("got '%v' want '%v'", match, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestGetLatestPrerelease(t *testing.T) {
+	var tests = []struct {
+		testName   string
+		versions   []string
+		channels   []string
+		wantLatest string
+	}{
+		{"should return a prerelease in a channel newer than the release", []string{"v1.0.0", "v1.1.0-rc.1", "v1.1.0-alpha.1"}, []string{"rc"}, "v1.1.0-rc.1"},
+		{"should return the release newer than the prereleases", []string{"v1.0.0-rc.1", "v1.0.0", "v0.9.0"}, []string{"rc"}, "v1.0.0"},
+		{"should leave out prereleases in other channels", []string{"v1.0.0", "v1.1.0-alpha.1"}, []string{"rc"}, "v1.0.0"},
+		{"should return nothing without versions", []string{}, []string{"rc"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			latest := bump.GetLatestPrerelease(mustParseVersions(t, tt.versions), tt.channels)
+
+			got := ""
+			if latest != nil {
+				got = latest.String()
+			}
+
+			if got != tt.wantLatest {
+				t.Errorf("got '%v' want '%v'", got, tt.wantLatest)
+			}
+		})
+	}
+}
+
+func TestGetCandidates(t *testing.T) {
+	versions := []string{"v1.0.0", "v1.1.0-rc.1", "v1.1.0-alpha.1", "v1.1.0", "v1.2.0", "v1.3.0-rc.1"}
+
+	var tests = []struct {
+		testName       string
+		lowestVersion  string
+		highestVersion string
+		channels       []string
+		wantCandidates []string
+	}{
+		{"should return the releases between the versions from highest to lowest", "v1.0.0", "v1.2.0", nil, []string{"v1.2.0", "v1.1.0"}},
+		{"should include the prereleases in a channel", "v1.0.0", "v1.3.0-rc.1", []string{"rc"}, []string{"v1.3.0-rc.1", "v1.2.0", "v1.1.0", "v1.1.0-rc.1"}},
+		{"should have no lower bound without a lowest version", "", "v1.1.0", nil, []string{"v1.1.0", "v1.0.0"}},
+		{"should have no upper bound without a highest version", "v1.1.0", "", []string{"rc"}, []string{"v1.3.0-rc.1", "v1.2.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			var lowestVersion, highestVersion *version.Version
+
+			if tt.lowestVersion != "" {
+				lowestVersion = mustParseVersion(t, tt.lowestVersion)
+			}
+
+			if tt.highestVersion != "" {
+				highestVersion = mustParseVersion(t, tt.highestVersion)
+			}
+
+			candidates := []string{}
+			for _, candidate := range bump.GetCandidates(mustParseVersions(t, versions), lowestVersion, highestVersion, tt.channels) {
+				candidates = append(candidates, candidate.String())
+			}
+
+			if !reflect.DeepEqual(candidates, tt.wantCandidates) {
+				t.Errorf("got '%v' want '%v'", candidates, tt.wantCandidates)
+			}
+		})
+	}
+}
+
+func TestConfigurationSatisfiesConstraintPrerelease(t *testing.T) {
+	conf := bump.Configuration{
+		Constraints: map[string]string{"github.com/acme/lib": ">= 1.2.0, < 2.0.0", "github.com/acme/api": ">= 1.2.0, < 2.0.0"},
+		Prereleases: []bump.PrereleaseConfig{{Modules: []string{"github.com/acme/lib"}, AllowPrerelease: []string{"rc"}}},
+	}
+
+	var tests = []struct {
+		testName      string
+		module        string
+		version       string
+		wantSatisfies bool
+	}{
+		{"should satisfy the constraint with a prerelease in a channel", "github.com/acme/lib", "v1.3.0-rc.1", true},
+		{"should not satisfy the constraint with a prerelease of a release outside of it", "github.com/acme/lib", "v2.0.0-rc.1", false},
+		{"should not satisfy the constraint with a prerelease in another channel", "github.com/acme/lib", "v1.3.0-alpha.1", false},
+		{"should not satisfy the constraint with a prerelease of a module without channels", "github.com/acme/api", "v1.3.0-rc.1", false},
+		{"should satisfy the constraint with a release", "github.com/acme/api", "v1.3.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			satisfies, err := conf.SatisfiesConstraint(tt.module, mustParseVersion(t, tt.version))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if satisfies != tt.wantSatisfies {
+				t.Errorf("got '%v' want '%v'", satisfies, tt.wantSatisfies)
+			}
+		})
+	}
+}
+
+func mustParseVersions(t *testing.T, versions []string) []*version.Version {
+	parsed := make([]*version.Version, 0, len(versions))
+
+	for _, v := range versions {
+		parsed = append(parsed, mustParseVersion(t, v))
+	}
+
+	return parsed
+}
@@ -0,0 +1,149 @@
+package bump
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/version"
+	"github.com/ryancurrah/gomodbump/vuln"
+)
+
+// ReplaceConfig is how modules replaced by a replace directive in the go.mod file are handled. Replaced
+// modules are never updated with `go get`, it has no effect while the replace directive is there.
+type ReplaceConfig struct {
+	Update bool `yaml:"update"`
+}
+
+// getReplacements returns the replace directives that apply to the build list and the updates of the
+// replacement versions. The replacements are checked for vulnerabilities instead of the replaced modules as
+// that is the code being built. Local path replacements are reported but never updated.
+func (b *Bumper) getReplacements(modules []*goListModule, goMod *goModFile) (repository.Replacements, repository.Updates, error) {
+	replacements := make(repository.Replacements, 0)
+	updates := make(repository.Updates, 0)
+
+	for _, module := range modules {
+		if module.Main || module.Replace == nil {
+			continue
+		}
+
+		replacement := &repository.Replacement{
+			Module:             module.Path,
+			Version:            module.Version,
+			Replacement:        module.Replace.Path,
+			ReplacementVersion: module.Replace.Version,
+		}
+
+		// A fork is behind when the upstream module has a newer release that was published after the fork version.
+		if replacement.IsFork() && module.Update != nil && module.Update.Time != nil && module.Replace.Time != nil &&
+			module.Update.Time.After(*module.Replace.Time) {
+			replacement.UpstreamVersion = module.Update.Version
+		}
+
+		vulnerabilities, err := b.findReplacementVulnerabilities(module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to find vulnerabilities of %s: %w", module.Replace.Path, err)
+		}
+
+		for n := range vulnerabilities {
+			replacement.Advisories = append(replacement.Advisories, newAdvisory(vulnerabilities[n]))
+		}
+
+		replacements = append(replacements, replacement)
+
+		update := b.getReplacementUpdate(module, goMod, vulnerabilities)
+		if update != nil {
+			updates = append(updates, update)
+		}
+	}
+
+	return replacements, updates, nil
+}
+
+// findReplacementVulnerabilities returns the vulnerabilities of the replacement version, local path replacements
+// have no version to check.
+func (b *Bumper) findReplacementVulnerabilities(module *goListModule) (vuln.Vulnerabilities, error) {
+	if b.vulnDB == nil || module.Replace.Version == "" {
+		return nil, nil
+	}
+
+	replacementVersion, err := version.Parse(module.Replace.Version)
+	if err != nil {
+		log.Printf("invalid replacement version '%s' for module '%s': %s", module.Replace.Version, module.Path, err)
+		return nil, nil
+	}
+
+	return b.vulnDB.Find(module.Replace.Path, replacementVersion)
+}
+
+// getReplacementUpdate returns the update of the replacement to its latest version or nil if it should not be
+// updated. A vulnerable replacement is updated to the version fixing it even if replacements are not updated
+// or the module is not allowed, like the modules that are not replaced.
+func (b *Bumper) getReplacementUpdate(module *goListModule, goMod *goModFile, vulnerabilities vuln.Vulnerabilities) *repository.Update {
+	if module.Replace.Version == "" {
+		return nil
+	}
+
+	oldVersion, err := version.Parse(module.Replace.Version)
+	if err != nil {
+		log.Printf("invalid replacement version '%s' for module '%s': %s", module.Replace.Version, module.Path, err)
+		return nil
+	}
+
+	var newVersion *version.Version
+
+	if b.conf.Replace.Update && !b.conf.Vulnerabilities.SecurityOnly && module.Replace.Update != nil && b.conf.IsModuleAllowed(module.Path) {
+		newVersion, err = version.Parse(module.Replace.Update.Version)
+		if err != nil {
+			log.Printf("invalid replacement version '%s' for module '%s': %s", module.Replace.Update.Version, module.Path, err)
+			return nil
+		}
+
+		satisfies, err := b.conf.satisfiesConstraint(module.Path, newVersion)
+		if err != nil || !satisfies {
+			newVersion = nil
+		}
+	}
+
+	// Fixing a vulnerability takes priority over the allow and block lists and the version policies.
+	fixedVersion := vulnerabilities.GetFixedVersion()
+	if fixedVersion != nil && (newVersion == nil || newVersion.LessThan(fixedVersion)) {
+		newVersion = fixedVersion
+	}
+
+	if newVersion == nil {
+		return nil
+	}
+
+	replace := goMod.getReplace(module.Path, module.Version)
+	if replace == nil {
+		return nil
+	}
+
+	return &repository.Update{
+		Module:          module.Path,
+		OldVersion:      oldVersion,
+		NewVersion:      newVersion,
+		Kind:            repository.ReplaceUpdate,
+		Replacement:     replace.New.Path,
+		ReplacedVersion: replace.Old.Version,
+		Advisories:      getAdvisories(vulnerabilities, newVersion),
+		Group:           b.conf.GetGroup(module.Path),
+	}
+}
+
+// updateReplacement changes the version of the replace directive.
+func updateReplacement(ctx context.Context, workingDir string, update *repository.Update) error {
+	old := update.Module
+	if update.ReplacedVersion != "" {
+		old = fmt.Sprintf("%s@%s", update.Module, update.ReplacedVersion)
+	}
+
+	err := runGoCommandWithEnv(ctx, workingDir, nil, "mod", "edit", fmt.Sprintf("-replace=%s=%s@%s", old, update.Replacement, update.NewVersion))
+	if err != nil {
+		return fmt.Errorf("failed to update replacement of module '%s': %s", update.Module, err)
+	}
+
+	return nil
+}
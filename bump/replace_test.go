@@ -0,0 +1,276 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/version"
+	"github.com/ryancurrah/gomodbump/vuln"
+)
+
+const testReplaceGoMod = `module git.acme.com/api
+
+go 1.21
+
+require (
+	github.com/acme/lib v1.0.0
+	github.com/acme/local v1.0.0
+	github.com/acme/pinned v1.0.0
+	github.com/acme/vulnerable v1.0.0
+)
+
+replace github.com/acme/lib => github.com/fork/lib v1.1.0
+
+replace github.com/acme/local => ../local
+
+replace github.com/acme/pinned v1.0.0 => github.com/acme/pinned v1.2.0
+
+replace github.com/acme/vulnerable => github.com/acme/vulnerable v1.3.0
+`
+
+const testReplaceGoList = `{
+	"Path": "git.acme.com/api",
+	"Main": true
+}
+{
+	"Path": "github.com/acme/lib",
+	"Version": "v1.0.0",
+	"Update": {"Path": "github.com/acme/lib", "Version": "v1.5.0", "Time": "2024-03-01T00:00:00Z"},
+	"Replace": {
+		"Path": "github.com/fork/lib",
+		"Version": "v1.1.0",
+		"Time": "2024-01-01T00:00:00Z",
+		"Update": {"Path": "github.com/fork/lib", "Version": "v1.2.0"}
+	}
+}
+{
+	"Path": "github.com/acme/local",
+	"Version": "v1.0.0",
+	"Replace": {"Path": "../local"}
+}
+{
+	"Path": "github.com/acme/pinned",
+	"Version": "v1.0.0",
+	"Replace": {"Path": "github.com/acme/pinned", "Version": "v1.2.0"}
+}
+{
+	"Path": "github.com/acme/vulnerable",
+	"Version": "v1.0.0",
+	"Replace": {
+		"Path": "github.com/acme/vulnerable",
+		"Version": "v1.3.0",
+		"Update": {"Path": "github.com/acme/vulnerable", "Version": "v1.3.1"}
+	}
+}
+`
+
+const testReplaceEntry = `{
+	"id": "GO-2024-0001",
+	"affected": [{
+		"package": {"name": "github.com/acme/vulnerable", "ecosystem": "Go"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.4.0"}]}]
+	}],
+	"database_specific": {"severity": "HIGH"}
+}`
+
+func newReplaceDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	err = ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(testReplaceGoMod), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Mkdir(filepath.Join(dir, "vulndb"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "vulndb", "GO-2024-0001.json"), []byte(testReplaceEntry), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestBumperGetReplacements(t *testing.T) {
+	dir := newReplaceDir(t)
+
+	bumper, err := bump.NewBumper(bump.Configuration{Vulnerabilities: vuln.Configuration{Dir: filepath.Join(dir, "vulndb")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replacements, _, err := bumper.GetReplacements(context.Background(), dir, []byte(testReplaceGoList))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(replacements) != 4 {
+		t.Fatalf("got %d replacements want 4", len(replacements))
+	}
+
+	var tests = []struct {
+		testName       string
+		replacement    int
+		wantModule     string
+		wantLocal      bool
+		wantFork       bool
+		wantUpstream   string
+		wantAdvisories int
+	}{
+		{"should report a fork behind upstream", 0, "github.com/acme/lib", false, true, "v1.5.0", 0},
+		{"should report a local replacement", 1, "github.com/acme/local", true, false, "", 0},
+		{"should report a version replacement", 2, "github.com/acme/pinned", false, false, "", 0},
+		{"should report the vulnerabilities of the replacement version", 3, "github.com/acme/vulnerable", false, false, "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			replacement := replacements[tt.replacement]
+
+			if replacement.Module != tt.wantModule {
+				t.Fatalf("got module '%s' want '%s'", replacement.Module, tt.wantModule)
+			}
+
+			if replacement.IsLocal() != tt.wantLocal || replacement.IsFork() != tt.wantFork {
+				t.Errorf("got local '%v' fork '%v' want local '%v' fork '%v'", replacement.IsLocal(), replacement.IsFork(), tt.wantLocal, tt.wantFork)
+			}
+
+			if replacement.UpstreamVersion != tt.wantUpstream {
+				t.Errorf("got upstream version '%s' want '%s'", replacement.UpstreamVersion, tt.wantUpstream)
+			}
+
+			if len(replacement.Advisories) != tt.wantAdvisories {
+				t.Errorf("got %d advisories want %d", len(replacement.Advisories), tt.wantAdvisories)
+			}
+		})
+	}
+}
+
+func TestBumperGetReplacementUpdates(t *testing.T) {
+	dir := newReplaceDir(t)
+	vulnConf := vuln.Configuration{Dir: filepath.Join(dir, "vulndb")}
+
+	var tests = []struct {
+		testName    string
+		config      bump.Configuration
+		wantUpdates map[string]string
+	}{
+		{
+			"should only fix vulnerable replacements when replacements are not updated",
+			bump.Configuration{Vulnerabilities: vulnConf},
+			map[string]string{"github.com/acme/vulnerable": "v1.4.0"},
+		},
+		{
+			"should update replacements to their latest version",
+			bump.Configuration{Vulnerabilities: vulnConf, Replace: bump.ReplaceConfig{Update: true}},
+			map[string]string{"github.com/acme/lib": "v1.2.0", "github.com/acme/vulnerable": "v1.4.0"},
+		},
+		{
+			"should only fix vulnerable replacements when updating for security only",
+			bump.Configuration{Vulnerabilities: vuln.Configuration{Dir: vulnConf.Dir, SecurityOnly: true}, Replace: bump.ReplaceConfig{Update: true}},
+			map[string]string{"github.com/acme/vulnerable": "v1.4.0"},
+		},
+		{
+			"should not update replacements of modules that are not allowed",
+			bump.Configuration{Replace: bump.ReplaceConfig{Update: true}, ModuleFilter: bump.ModuleFilter{BlockedModules: []string{"github.com/acme/lib"}}},
+			map[string]string{"github.com/acme/vulnerable": "v1.3.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			bumper, err := bump.NewBumper(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, updates, err := bumper.GetReplacements(context.Background(), dir, []byte(testReplaceGoList))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := make(map[string]string, len(updates))
+
+			for n := range updates {
+				if updates[n].Kind != repository.ReplaceUpdate {
+					t.Errorf("got kind '%s' want '%s'", updates[n].Kind, repository.ReplaceUpdate)
+				}
+
+				got[updates[n].Module] = updates[n].NewVersion.String()
+			}
+
+			if len(got) != len(tt.wantUpdates) {
+				t.Fatalf("got updates %v want %v", got, tt.wantUpdates)
+			}
+
+			for module, wantVersion := range tt.wantUpdates {
+				if got[module] != wantVersion {
+					t.Errorf("got %s at '%s' want '%s'", module, got[module], wantVersion)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateReplacement(t *testing.T) {
+	var tests = []struct {
+		testName    string
+		update      *repository.Update
+		wantReplace string
+	}{
+		{
+			"should update a fork",
+			&repository.Update{Module: "github.com/acme/lib", Replacement: "github.com/fork/lib", NewVersion: mustParseVersion(t, "v1.2.0")},
+			"github.com/acme/lib => github.com/fork/lib v1.2.0",
+		},
+		{
+			"should keep the replaced version",
+			&repository.Update{Module: "github.com/acme/pinned", Replacement: "github.com/acme/pinned", ReplacedVersion: "v1.0.0", NewVersion: mustParseVersion(t, "v1.3.0")},
+			"github.com/acme/pinned v1.0.0 => github.com/acme/pinned v1.3.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := newReplaceDir(t)
+
+			err := bump.UpdateReplacement(context.Background(), dir, tt.update)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			goMod, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(goMod), tt.wantReplace) {
+				t.Errorf("got go.mod file\n%s\nwant replace directive '%s'", goMod, tt.wantReplace)
+			}
+		})
+	}
+}
+
+func mustParseVersion(t *testing.T, s string) *version.Version {
+	v, err := version.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
@@ -0,0 +1,197 @@
+package bump
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/version"
+	"github.com/ryancurrah/gomodbump/vuln"
+)
+
+// GroupConfig groups the updates of modules or module domains together in the pull request.
+type GroupConfig struct {
+	Name    string   `yaml:"name"`
+	Modules []string `yaml:"modules"`
+	Domains []string `yaml:"domains"`
+}
+
+// RepositoryConfig are the bump settings a repository can set in its own .gomodbump.yaml file. The
+// allow and block lists can only narrow what the central configuration allows.
+type RepositoryConfig struct {
+	ModuleFilter `yaml:",inline"`
+	Constraints  map[string]string `yaml:"constraints"`
+	Groups       []GroupConfig     `yaml:"groups"`
+	Ignore       []IgnoreConfig    `yaml:"ignore"`
+}
+
+// Merge the repository settings over the configuration. Blocked lists are added to the central ones and
+// allowed lists have to allow the module as well as the central ones. Constraints for the same module and
+// groups matching the same module from the repository take precedence, ignored versions are added.
+func (c Configuration) Merge(repoConf RepositoryConfig) Configuration {
+	merged := c
+
+	merged.RepositoryFilter = ModuleFilter{
+		AllowedModules: append(append([]string{}, c.RepositoryFilter.AllowedModules...), repoConf.AllowedModules...),
+		AllowedDomains: append(append([]string{}, c.RepositoryFilter.AllowedDomains...), repoConf.AllowedDomains...),
+		BlockedModules: append(append([]string{}, c.RepositoryFilter.BlockedModules...), repoConf.BlockedModules...),
+		BlockedDomains: append(append([]string{}, c.RepositoryFilter.BlockedDomains...), repoConf.BlockedDomains...),
+	}
+
+	merged.Constraints = make(map[string]string, len(c.Constraints)+len(repoConf.Constraints))
+
+	for module, constraint := range c.Constraints {
+		merged.Constraints[module] = constraint
+	}
+
+	for module, constraint := range repoConf.Constraints {
+		merged.Constraints[module] = constraint
+	}
+
+	merged.Groups = append(append([]GroupConfig{}, repoConf.Groups...), c.Groups...)
+	merged.Ignore = append(append([]IgnoreConfig{}, c.Ignore...), repoConf.Ignore...)
+
+	return merged
+}
+
+// Target returns the configuration that only updates the module, if the allow and block lists allow it, so a
+// release of the module is bumped without the other updates. The go directive, the vulnerable modules and the
+// replace directives are not updated.
+func (c Configuration) Target(module string) Configuration {
+	targeted := c
+
+	targeted.TargetFilter = ModuleFilter{AllowedModules: []string{module}}
+	targeted.GoVersion = GoVersionConfig{}
+	targeted.Vulnerabilities = vuln.Configuration{SecurityOnly: c.Vulnerabilities.SecurityOnly}
+	targeted.Replace = ReplaceConfig{}
+
+	return targeted
+}
+
+// Validate returns an error if any of the module patterns, ignored versions or constraints are invalid.
+func (c RepositoryConfig) Validate() error {
+	err := c.ModuleFilter.Validate()
+	if err != nil {
+		return err
+	}
+
+	for _, ignore := range c.Ignore {
+		err = ignore.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, group := range c.Groups {
+		for _, pattern := range group.Modules {
+			err = validateModulePattern(pattern)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for module, constraint := range c.Constraints {
+		_, err = semver.NewConstraint(constraint)
+		if err != nil {
+			return fmt.Errorf("invalid constraint '%s' for module '%s': %s", constraint, module, err)
+		}
+	}
+
+	return nil
+}
+
+// IsModuleAllowed returns true if the module is allowed to be updated by both the central allow and block
+// lists and the ones from the repository, and is the module of a targeted bump if any.
+func (c Configuration) IsModuleAllowed(module string) bool {
+	return c.ModuleFilter.IsModuleAllowed(module) && c.RepositoryFilter.IsModuleAllowed(module) &&
+		c.TargetFilter.IsModuleAllowed(module)
+}
+
+// IsIndirectModuleAllowed returns true if the indirect module is allowed to be updated by both the central
+// indirect allow and block lists and the ones from the repository, and is the module of a targeted bump if any.
+func (c Configuration) IsIndirectModuleAllowed(module string) bool {
+	return c.Indirect.IsModuleAllowed(module) && c.RepositoryFilter.IsModuleAllowed(module) &&
+		c.TargetFilter.IsModuleAllowed(module)
+}
+
+// IsToolModuleAllowed returns true if the module providing a tool is allowed to be updated by both the central
+// tools allow and block lists and the ones from the repository, and is the module of a targeted bump if any.
+func (c Configuration) IsToolModuleAllowed(module string) bool {
+	return c.Tools.IsModuleAllowed(module) && c.RepositoryFilter.IsModuleAllowed(module) &&
+		c.TargetFilter.IsModuleAllowed(module)
+}
+
+// IsVersionAllowed returns true if the module version is not blocked by the allow and block lists of the kind
+// of update, e.g. the indirect ones for an indirect update, or by the ones from the repository.
+func (c Configuration) IsVersionAllowed(kind repository.UpdateKind, module, moduleVersion string) bool {
+	return c.getKindFilter(kind).IsVersionAllowed(module, moduleVersion) && c.RepositoryFilter.IsVersionAllowed(module, moduleVersion)
+}
+
+// hasBlockedVersions returns true if any version of the module is blocked by the allow and block lists of the
+// kind of update or the ones from the repository.
+func (c Configuration) hasBlockedVersions(kind repository.UpdateKind, module string) bool {
+	return c.getKindFilter(kind).hasBlockedVersions(module) || c.RepositoryFilter.hasBlockedVersions(module)
+}
+
+// getKindFilter returns the central allow and block lists of the kind of update.
+func (c Configuration) getKindFilter(kind repository.UpdateKind) ModuleFilter {
+	switch kind {
+	case repository.IndirectUpdate:
+		return c.Indirect
+	case repository.ToolUpdate:
+		return c.Tools
+	default:
+		return c.ModuleFilter
+	}
+}
+
+// GetGroup returns the name of the first group the module is in, or an empty string if it is not in any.
+func (c Configuration) GetGroup(module string) string {
+	for _, group := range c.Groups {
+		for _, groupModule := range group.Modules {
+			if matchModulePattern(groupModule, module) {
+				return group.Name
+			}
+		}
+
+		for _, groupDomain := range group.Domains {
+			if matchDomain(groupDomain, module) {
+				return group.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// satisfiesConstraint returns true if the module has no constraint or the version meets it. A constraint without a
+// prerelease never matches a prerelease, so a prerelease in one of the module's prerelease channels is checked as
+// the release it leads to, e.g. v1.3.0-rc.1 as v1.3.0.
+func (c Configuration) satisfiesConstraint(module string, moduleVersion *version.Version) (bool, error) {
+	constraint, ok := c.Constraints[module]
+	if !ok {
+		return true, nil
+	}
+
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid constraint '%s' for module '%s': %s", constraint, module, err)
+	}
+
+	semverVersion, err := semver.NewVersion(moduleVersion.String())
+	if err != nil {
+		return false, nil
+	}
+
+	if moduleVersion.IsPrerelease() && isPrereleaseInChannels(moduleVersion, c.GetAllowedPrereleases(module)) {
+		release, err := semverVersion.SetPrerelease("")
+		if err != nil {
+			return false, nil
+		}
+
+		semverVersion = &release
+	}
+
+	return constraints.Check(semverVersion), nil
+}
@@ -0,0 +1,75 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+func TestConfigurationMerge(t *testing.T) {
+	conf := bump.Configuration{
+		ModuleFilter: bump.ModuleFilter{AllowedDomains: []string{"github.com/acme"}},
+		Constraints:  map[string]string{"github.com/acme/lib": "< 2.0.0", "github.com/acme/api": "< 3.0.0"},
+		Groups:       []bump.GroupConfig{{Name: "central", Domains: []string{"github.com/acme"}}},
+		Ignore:       []bump.IgnoreConfig{{Module: "github.com/acme/lib", Versions: []string{"v1.5.3"}}},
+	}
+
+	repoConf := bump.RepositoryConfig{
+		ModuleFilter: bump.ModuleFilter{AllowedModules: []string{"github.com/acme/lib", "github.com/acme/api", "github.com/other/lib"}},
+		Constraints:  map[string]string{"github.com/acme/lib": "< 1.6.0"},
+		Groups:       []bump.GroupConfig{{Name: "repo", Modules: []string{"github.com/acme/lib"}}},
+		Ignore:       []bump.IgnoreConfig{{Module: "github.com/acme/api", Versions: []string{"v2.0.0"}}},
+	}
+
+	merged := conf.Merge(repoConf)
+
+	var tests = []struct {
+		testName      string
+		module        string
+		version       string
+		wantAllowed   bool
+		wantSatisfies bool
+		wantIgnored   bool
+		wantGroup     string
+	}{
+		{"should use the constraint and group of the repository", "github.com/acme/lib", "v1.7.0", true, false, false, "repo"},
+		{"should keep the ignored versions of the central configuration", "github.com/acme/lib", "v1.5.3", true, true, true, "repo"},
+		{"should keep the constraint and group of the central configuration", "github.com/acme/api", "v2.5.0", true, true, false, "central"},
+		{"should add the ignored versions of the repository", "github.com/acme/api", "v2.0.0", true, true, true, "central"},
+		{"should block a module allowed by the central configuration but not the repository", "github.com/acme/old", "v1.0.0", false, true, false, "central"},
+		{"should block a module allowed by the repository but not the central configuration", "github.com/other/lib", "v1.0.0", false, true, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			moduleVersion := mustParseVersion(t, tt.version)
+
+			if allowed := merged.IsModuleAllowed(tt.module); allowed != tt.wantAllowed {
+				t.Errorf("got allowed '%v' want '%v'", allowed, tt.wantAllowed)
+			}
+
+			satisfies, err := merged.SatisfiesConstraint(tt.module, moduleVersion)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if satisfies != tt.wantSatisfies {
+				t.Errorf("got satisfies '%v' want '%v'", satisfies, tt.wantSatisfies)
+			}
+
+			if ignored := merged.IsVersionIgnored(tt.module, moduleVersion, time.Now()); ignored != tt.wantIgnored {
+				t.Errorf("got ignored '%v' want '%v'", ignored, tt.wantIgnored)
+			}
+
+			if group := merged.GetGroup(tt.module); group != tt.wantGroup {
+				t.Errorf("got group '%v' want '%v'", group, tt.wantGroup)
+			}
+		})
+	}
+
+	if len(conf.Ignore) != 1 || conf.Constraints["github.com/acme/lib"] != "< 2.0.0" {
+		t.Errorf("got the configuration changed by Merge want it unchanged")
+	}
+}
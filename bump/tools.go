@@ -0,0 +1,132 @@
+package bump
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// toolsBuildTag is the build tag of the file that imports the tools of a module, usually named tools.go.
+const toolsBuildTag = "tools"
+
+// getToolModules returns the modules that provide the tools of the main module. Tools are declared with tool
+// directives in the go.mod file or blank imported in a file with the tools build constraint.
+func getToolModules(workingDir string, goMod *goModFile, modules []*goListModule) (map[string]bool, error) {
+	packages, err := getToolsFilePackages(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for n := range goMod.Tool {
+		packages = append(packages, goMod.Tool[n].Path)
+	}
+
+	toolModules := make(map[string]bool)
+
+	for _, pkg := range packages {
+		module := getPackageModule(pkg, modules)
+		if module != "" {
+			toolModules[module] = true
+		}
+	}
+
+	return toolModules, nil
+}
+
+// getToolsFilePackages returns the packages imported by the files with the tools build constraint.
+func getToolsFilePackages(workingDir string) ([]string, error) {
+	packages := make([]string, 0)
+
+	err := filepath.Walk(workingDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != workingDir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			// Files the go command cannot parse either are not our concern.
+			return nil // nolint: nilerr
+		}
+
+		if !hasToolsBuildConstraint(file.Comments, file.Package) {
+			return nil
+		}
+
+		for _, spec := range file.Imports {
+			pkg, err := strconv.Unquote(spec.Path.Value)
+			if err == nil {
+				packages = append(packages, pkg)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+// hasToolsBuildConstraint returns true if a build constraint before the package clause requires the tools build tag.
+func hasToolsBuildConstraint(comments []*ast.CommentGroup, packagePos token.Pos) bool {
+	for _, group := range comments {
+		if group.Pos() > packagePos {
+			break
+		}
+
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) && !constraint.IsPlusBuild(comment.Text) {
+				continue
+			}
+
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
+			}
+
+			// Files that build without the tools build tag are not tools files, e.g. tools || linux.
+			if expr.Eval(func(tag string) bool { return tag == toolsBuildTag }) && !expr.Eval(func(tag string) bool { return tag != toolsBuildTag }) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// getPackageModule returns the path of the module in the build list that provides the package, the
+// module with the longest path prefix. An empty string is returned if no module provides it.
+func getPackageModule(pkg string, modules []*goListModule) string {
+	module := ""
+
+	for n := range modules {
+		if modules[n].Main {
+			continue
+		}
+
+		if (pkg == modules[n].Path || strings.HasPrefix(pkg, modules[n].Path+"/")) && len(modules[n].Path) > len(module) {
+			module = modules[n].Path
+		}
+	}
+
+	return module
+}
@@ -0,0 +1,85 @@
+// nolint:scopelint
+package bump_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+const testToolsGoList = `{"Path": "git.acme.com/api", "Main": true}
+{"Path": "golang.org/x/tools", "Version": "v0.20.0"}
+{"Path": "golang.org/x/tools/gopls", "Version": "v0.15.0"}
+{"Path": "github.com/golangci/golangci-lint", "Version": "v1.57.0"}
+{"Path": "github.com/acme/gen", "Version": "v1.0.0"}
+{"Path": "github.com/acme/mockgen", "Version": "v1.0.0"}
+{"Path": "github.com/acme/lib", "Version": "v1.0.0"}
+{"Path": "github.com/acme/vendored", "Version": "v1.0.0"}
+{"Path": "github.com/acme/linux", "Version": "v1.0.0"}
+{"Path": "github.com/acme/unix", "Version": "v1.0.0"}
+`
+
+var testToolsFiles = map[string]string{
+	"tools.go":                "//go:build tools\n\npackage api\n\nimport (\n\t_ \"github.com/golangci/golangci-lint/cmd/golangci-lint\"\n\t_ \"golang.org/x/tools/gopls\"\n)\n",
+	"internal/tools/tools.go": "// +build tools\n\npackage tools\n\nimport _ \"github.com/acme/gen/cmd/gen\"\n",
+	"main.go":                 "package api\n\nimport _ \"github.com/acme/lib\"\n",
+	"linux.go":                "//go:build tools || linux\n\npackage api\n\nimport _ \"github.com/acme/linux\"\n",
+	"unix.go":                 "//go:build tools && !windows\n\npackage api\n\nimport _ \"github.com/acme/unix\"\n",
+	"vendor/tools.go":         "//go:build tools\n\npackage vendor\n\nimport _ \"github.com/acme/vendored\"\n",
+	"testdata/tools.go":       "//go:build tools\n\npackage testdata\n\nimport _ \"github.com/acme/vendored\"\n",
+	"late.go":                 "package api\n\n//go:build tools\n\nimport _ \"github.com/acme/vendored\"\n",
+	"broken.go":               "//go:build tools\n\npackage api\n\nimport _ \"github.com/acme/vendored\n",
+}
+
+func TestGetToolModules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, content := range testToolsFiles {
+		err = os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	toolModules, err := bump.GetToolModules(dir, []string{"github.com/acme/mockgen", "github.com/other/missing"}, []byte(testToolsGoList))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var tests = []struct {
+		testName string
+		module   string
+		wantTool bool
+	}{
+		{"should find a module imported by a go:build tools file", "github.com/golangci/golangci-lint", true},
+		{"should find a module imported by a +build tools file", "github.com/acme/gen", true},
+		{"should find the module with the longest path prefix", "golang.org/x/tools/gopls", true},
+		{"should not find the parent of a nested module", "golang.org/x/tools", false},
+		{"should find a module of a tool directive", "github.com/acme/mockgen", true},
+		{"should not find a module imported without the tools build tag", "github.com/acme/lib", false},
+		{"should find a module imported by a tools file with other constraints", "github.com/acme/unix", true},
+		{"should not find a module imported by a file that also builds without the tools build tag", "github.com/acme/linux", false},
+		{"should not find a module only imported in vendor, testdata, unparsable files or after the package clause", "github.com/acme/vendored", false},
+		{"should not find a tool missing from the build list", "github.com/other/missing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			if toolModules[tt.module] != tt.wantTool {
+				t.Errorf("got tool '%v' want '%v'", toolModules[tt.module], tt.wantTool)
+			}
+		})
+	}
+}
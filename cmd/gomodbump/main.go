@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/ryancurrah/gomodbump"
@@ -17,28 +24,352 @@ var (
 	logger     = log.New(os.Stderr, "", 0)
 )
 
+// errUsage is returned when the command, its flags or its arguments are invalid.
+var errUsage = errors.New("invalid usage")
+
+const (
+	// Exit code used when the command failed.
+	exitCodeFailed = 1
+	// Exit code used when the run completed but module updates could not be found for some repos.
+	exitCodeDiscoveryFailed = 2
+	// Exit code used when the run completed but processing some repos failed.
+	exitCodeRepositoriesFailed = 3
+	// Exit code used when the command or its flags are invalid.
+	exitCodeUsage = 64
+	// Exit code used when the run was interrupted by a signal.
+	exitCodeInterrupted = 130
+)
+
+// Log levels. Debug also logs the source of the messages, error only logs the failures and does not print the git
+// progress.
+const (
+	debugLogLevel = "debug"
+	infoLogLevel  = "info"
+	errorLogLevel = "error"
+)
+
+// command is a gomodbump subcommand.
+type command struct {
+	name        string
+	args        string
+	description string
+	run         func(ctx context.Context, flags *flag.FlagSet, conf *gomodbump.Configuration, args []string) error
+}
+
+var commands = []command{
+	{"run", "", "Merge, bump, push and create pull requests for all the repositories (default)", runCommand},
+	{"plan", "", "Print what run would do without changing any repositories", planCommand},
+	{"serve", "", "Run on the serve schedule and receive tag webhooks until interrupted", serveCommand},
+	{"status", "", "Print the repositories with an open pull request from the stored state", statusCommand},
+	{"bump", "<repo|./dir>", "Merge, bump, push and create a pull request for one repository, or bump a directory in place", bumpCommand},
+	{"validate-config", "", "Validate the configuration file and exit", validateConfigCommand},
+}
+
 func main() {
-	config, err := getConfig()
+	os.Exit(execute(os.Args[1:]))
+}
+
+// execute runs the command of the arguments and returns the exit code.
+func execute(arguments []string) int {
+	flags := flag.NewFlagSet("gomodbump", flag.ContinueOnError)
+	flags.Usage = func() { usage(flags) }
+
+	config := flags.String("config", "", fmt.Sprintf("path of the configuration file (default %s in the current or home directory)", configFile))
+	logLevel := flags.String("log-level", infoLogLevel, "log level, one of debug, info or error, debug adds the source of the messages and error only logs the failures without the git progress")
+	workers := flags.Int("workers", 0, "number of repositories to process concurrently, overrides general.workers")
+
+	err := parseFlags(flags, arguments)
 	if err != nil {
-		logger.Fatal(err)
+		return getExitCode(err)
 	}
 
-	config.SCM.BitbucketServer.Username = os.Getenv("BITBUCKET_SERVER_USERNAME")
-	config.SCM.BitbucketServer.Password = os.Getenv("BITBUCKET_SERVER_PASSWORD")
-	config.SCM.BitbucketServer.Token = os.Getenv("BITBUCKET_SERVER_TOKEN")
-	config.VCS.Git.Username = os.Getenv("GIT_USERNAME")
-	config.VCS.Git.Password = os.Getenv("GIT_PASSWORD")
-	config.VCS.Git.Token = os.Getenv("GIT_TOKEN")
+	err = setLogLevel(*logLevel)
+	if err != nil {
+		logger.Print(err)
+		return exitCodeUsage
+	}
 
-	bumper, err := gomodbump.NewGoModBump(*config)
+	args := flags.Args()
+	if len(args) == 0 {
+		args = []string{"run"}
+	}
+
+	cmd := getCommand(args[0])
+	if cmd == nil {
+		logger.Printf("unknown command '%s'", args[0])
+		flags.Usage()
+
+		return exitCodeUsage
+	}
+
+	// Unknown configuration keys are only an error when validating, so older binaries can still run newer
+	// configuration files.
+	conf, err := getConfig(*config, cmd.name == "validate-config")
 	if err != nil {
-		log.Fatalf("running gomodbump failed: %s", err)
+		logger.Print(err)
+		return exitCodeFailed
+	}
+
+	if *workers > 0 {
+		conf.General.Workers = *workers
+	}
+
+	conf.VCS.Git.Progress = *logLevel != errorLogLevel
+
+	conf.SCM.BitbucketServer.Username = os.Getenv("BITBUCKET_SERVER_USERNAME")
+	conf.SCM.BitbucketServer.Password = os.Getenv("BITBUCKET_SERVER_PASSWORD")
+	conf.SCM.BitbucketServer.Token = os.Getenv("BITBUCKET_SERVER_TOKEN")
+	conf.VCS.Git.Username = os.Getenv("GIT_USERNAME")
+	conf.VCS.Git.Password = os.Getenv("GIT_PASSWORD")
+	conf.VCS.Git.Token = os.Getenv("GIT_TOKEN")
+	conf.Serve.Webhook.Secret = os.Getenv("WEBHOOK_SECRET")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cancelOnSignal(cancel)
+
+	err = cmd.run(ctx, newCommandFlagSet(cmd), conf, args[1:])
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		logger.Printf("running gomodbump %s failed: %s", cmd.name, err)
+	}
+
+	return getExitCode(err)
+}
+
+// getExitCode returns the exit code of the error the command failed with.
+func getExitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, gomodbump.ErrInterrupted), errors.Is(err, context.Canceled):
+		return exitCodeInterrupted
+	case errors.Is(err, gomodbump.ErrUpdateDiscoveryFailed):
+		return exitCodeDiscoveryFailed
+	case errors.Is(err, gomodbump.ErrRepositoriesFailed):
+		return exitCodeRepositoriesFailed
+	case errors.Is(err, errUsage):
+		return exitCodeUsage
+	default:
+		return exitCodeFailed
+	}
+}
+
+// parseFlags parses the flags of a command, invalid flags are a usage error.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return fmt.Errorf("%w: %s", errUsage, err)
 	}
 
-	err = bumper.Run()
+	return err
+}
+
+func runCommand(ctx context.Context, flags *flag.FlagSet, conf *gomodbump.Configuration, args []string) error {
+	dryRun := flags.Bool("dry-run", false, "same as the plan command")
+	planFile := flags.String("plan-file", "", "also write the dry run plan to this file as JSON")
+
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		return plan(ctx, conf, *planFile)
+	}
+
+	bumper, err := gomodbump.NewGoModBump(*conf)
+	if err != nil {
+		return err
+	}
+
+	return bumper.Run(ctx)
+}
+
+func planCommand(ctx context.Context, flags *flag.FlagSet, conf *gomodbump.Configuration, args []string) error {
+	output := flags.String("output", "", "also write the plan to this file as JSON")
+
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	return plan(ctx, conf, *output)
+}
+
+func plan(ctx context.Context, conf *gomodbump.Configuration, planFile string) error {
+	bumper, err := gomodbump.NewGoModBump(*conf)
+	if err != nil {
+		return err
+	}
+
+	plan, err := bumper.Plan(ctx)
+	if plan == nil {
+		return err
+	}
+
+	plan.Print(os.Stdout)
+
+	if planFile != "" {
+		errWrite := plan.WriteJSON(planFile)
+		if errWrite != nil {
+			return fmt.Errorf("writing plan failed: %s", errWrite)
+		}
+	}
+
+	return err
+}
+
+func serveCommand(ctx context.Context, flags *flag.FlagSet, conf *gomodbump.Configuration, args []string) error {
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	bumper, err := gomodbump.NewGoModBump(*conf)
 	if err != nil {
-		log.Fatalf("running gomodbump failed: %s", err)
+		return err
 	}
+
+	return bumper.Serve(ctx)
+}
+
+func statusCommand(ctx context.Context, flags *flag.FlagSet, conf *gomodbump.Configuration, args []string) error {
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	repos, err := gomodbump.Status(ctx, *conf)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // nolint: gomnd
+
+	fmt.Fprintln(writer, "REPOSITORY\tPULL REQUEST\tSOURCE BRANCH\tTARGET BRANCH\tUPDATES")
+
+	for _, repo := range repos {
+		fmt.Fprintf(writer, "%s/%s\t#%d\t%s\t%s\t%d\n", repo.Parent, repo.Name, repo.PullRequestID, repo.SourceBranch, repo.TargetBranch, len(repo.Updates))
+	}
+
+	return writer.Flush()
+}
+
+func bumpCommand(ctx context.Context, flags *flag.FlagSet, conf *gomodbump.Configuration, args []string) error {
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: bump requires the name of one repository or the path of a directory", errUsage)
+	}
+
+	// A directory is bumped in place, without cloning, pushing or creating a pull request. It has to be given as a
+	// path so a repository is never mistaken for a directory with the same name.
+	if isPath(flags.Arg(0)) {
+		if !dirExists(flags.Arg(0)) {
+			return fmt.Errorf("directory '%s' does not exist", flags.Arg(0))
+		}
+
+		repoPlan, err := gomodbump.BumpLocal(ctx, *conf, flags.Arg(0))
+		if repoPlan != nil {
+			repoPlan.Print(os.Stdout)
+		}
+
+		return err
+	}
+
+	bumper, err := gomodbump.NewGoModBump(*conf)
+	if err != nil {
+		return err
+	}
+
+	return bumper.RunRepository(ctx, flags.Arg(0))
+}
+
+func validateConfigCommand(ctx context.Context, flags *flag.FlagSet, conf *gomodbump.Configuration, args []string) error {
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	err = conf.Validate()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("configuration is valid")
+
+	return nil
+}
+
+func newCommandFlagSet(cmd *command) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: gomodbump [flags] %s [%s flags] %s\n\n%s\n\nFlags:\n", cmd.name, cmd.name, cmd.args, cmd.description)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+func usage(flags *flag.FlagSet) {
+	output := flags.Output()
+
+	fmt.Fprint(output, "Usage: gomodbump [flags] [command] [command flags]\n\nCommands:\n")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(output, "  %-30s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.description)
+	}
+
+	fmt.Fprint(output, "\nFlags:\n")
+	flags.PrintDefaults()
+}
+
+// cancelOnSignal cancels the context when SIGINT or SIGTERM is received, the repositories being processed are
+// finished and the state is saved. A second signal exits immediately.
+func cancelOnSignal(cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 1)
+
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signals
+
+		logger.Printf("received %s, finishing the repositories being processed and saving the state, send it again to exit now", sig)
+
+		signal.Stop(signals)
+
+		cancel()
+	}()
+}
+
+func getCommand(name string) *command {
+	for n := range commands {
+		if commands[n].name == name {
+			return &commands[n]
+		}
+	}
+
+	return nil
+}
+
+func setLogLevel(logLevel string) error {
+	switch logLevel {
+	case debugLogLevel:
+		log.SetFlags(log.LstdFlags | log.Lmicroseconds | log.Lshortfile)
+		gomodbump.ErrorLog.SetFlags(log.LstdFlags | log.Lmicroseconds | log.Lshortfile)
+	case infoLogLevel:
+	case errorLogLevel:
+		log.SetOutput(ioutil.Discard)
+	default:
+		return fmt.Errorf("invalid log level '%s', expected debug, info or error", logLevel)
+	}
+
+	return nil
 }
 
 func fileExists(filename string) bool {
@@ -50,24 +381,44 @@ func fileExists(filename string) bool {
 	return !info.IsDir()
 }
 
-func getConfig() (*gomodbump.Configuration, error) {
-	config := gomodbump.Configuration{}
+// isPath returns true if the argument is an absolute path or a path relative to the current directory, such as
+// ./api, rather than the name of a repository.
+func isPath(arg string) bool {
+	arg = filepath.ToSlash(arg)
+
+	return arg == "." || arg == ".." || strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../") || filepath.IsAbs(arg)
+}
 
-	home, err := homedir.Dir()
+func dirExists(dirname string) bool {
+	info, err := os.Stat(dirname)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find home directory, %s", err)
+		return false
 	}
 
-	cfgFile := ""
-	homeDirCfgFile := filepath.Join(home, configFile)
+	return info.IsDir()
+}
 
-	switch {
-	case fileExists(configFile):
-		cfgFile = configFile
-	case fileExists(homeDirCfgFile):
-		cfgFile = homeDirCfgFile
-	default:
-		return nil, fmt.Errorf("could not find config file in %s, %s", configFile, homeDirCfgFile)
+// getConfig reads the configuration file provided or the first one found in the current or home directory.
+// If strict is true, unknown keys and duplicate keys are an error.
+func getConfig(cfgFile string, strict bool) (*gomodbump.Configuration, error) {
+	config := gomodbump.Configuration{}
+
+	if cfgFile == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to find home directory, %s", err)
+		}
+
+		homeDirCfgFile := filepath.Join(home, configFile)
+
+		switch {
+		case fileExists(configFile):
+			cfgFile = configFile
+		case fileExists(homeDirCfgFile):
+			cfgFile = homeDirCfgFile
+		default:
+			return nil, fmt.Errorf("could not find config file in %s, %s", configFile, homeDirCfgFile)
+		}
 	}
 
 	data, err := ioutil.ReadFile(cfgFile)
@@ -75,7 +426,12 @@ func getConfig() (*gomodbump.Configuration, error) {
 		return nil, fmt.Errorf("could not read config file: %s", err)
 	}
 
-	err = yaml.Unmarshal(data, &config)
+	unmarshal := yaml.Unmarshal
+	if strict {
+		unmarshal = yaml.UnmarshalStrict
+	}
+
+	err = unmarshal(data, &config)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse config file: %s", err)
 	}
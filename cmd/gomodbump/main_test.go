@@ -0,0 +1,97 @@
+// nolint:scopelint
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump"
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+func TestExecute(t *testing.T) {
+	dir := t.TempDir()
+
+	validConfig := writeConfig(t, dir, "valid.yaml", fmt.Sprintf("general:\n  workers: 1\n  work_dir: %s\n", filepath.Join(dir, "work")))
+	invalidConfig := writeConfig(t, dir, "invalid.yaml", fmt.Sprintf("general:\n  workers: 0\n  work_dir: %s\n", filepath.Join(dir, "work")))
+	unknownKeyConfig := writeConfig(t, dir, "unknown.yaml", fmt.Sprintf("general:\n  workers: 1\n  work_dir: %s\n  unknown: true\n", filepath.Join(dir, "work")))
+
+	var tests = []struct {
+		testName     string
+		args         []string
+		wantExitCode int
+	}{
+		{"should exit with 0 for help", []string{"-h"}, 0},
+		{"should exit with 64 for an unknown flag", []string{"-unknown"}, exitCodeUsage},
+		{"should exit with 64 for an invalid log level", []string{"-log-level", "trace", "validate-config"}, exitCodeUsage},
+		{"should exit with 64 for an unknown command", []string{"-config", validConfig, "unknown"}, exitCodeUsage},
+		{"should exit with 1 for a missing configuration file", []string{"-config", filepath.Join(dir, "missing.yaml"), "validate-config"}, exitCodeFailed},
+		{"should exit with 0 for a valid configuration", []string{"-config", validConfig, "validate-config"}, 0},
+		{"should exit with 1 for an invalid configuration", []string{"-config", invalidConfig, "validate-config"}, exitCodeFailed},
+		{"should exit with 1 for an unknown configuration key when validating", []string{"-config", unknownKeyConfig, "validate-config"}, exitCodeFailed},
+		{"should exit with 0 for the help of a command", []string{"-config", validConfig, "plan", "-h"}, 0},
+		{"should exit with 64 for an unknown flag of a command", []string{"-config", validConfig, "plan", "-unknown"}, exitCodeUsage},
+		{"should exit with 64 for bump without a repository", []string{"-config", validConfig, "bump"}, exitCodeUsage},
+		{"should exit with 64 for bump with several repositories", []string{"-config", validConfig, "bump", "api", "lib"}, exitCodeUsage},
+		{"should exit with 1 for bump with a missing directory", []string{"-config", validConfig, "bump", filepath.Join(dir, "missing")}, exitCodeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			exitCode := execute(tt.args)
+			if exitCode != tt.wantExitCode {
+				t.Errorf("got '%v' want '%v'", exitCode, tt.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestGetExitCode(t *testing.T) {
+	var tests = []struct {
+		testName     string
+		err          error
+		wantExitCode int
+	}{
+		{"should exit with 0 without an error", nil, 0},
+		{"should exit with 0 for help", flag.ErrHelp, 0},
+		{"should exit with 130 when interrupted", gomodbump.ErrInterrupted, exitCodeInterrupted},
+		{"should exit with 130 when cancelled", fmt.Errorf("cloning failed: %w", context.Canceled), exitCodeInterrupted},
+		{
+			"should exit with 2 when finding module updates failed",
+			&gomodbump.RunError{Failures: []*gomodbump.RepositoryFailure{{Stage: gomodbump.BumpStage, Err: &bump.DiscoveryError{Err: errors.New("go list failed")}}}},
+			exitCodeDiscoveryFailed,
+		},
+		{
+			"should exit with 3 when processing repositories failed",
+			&gomodbump.RunError{Failures: []*gomodbump.RepositoryFailure{{Stage: gomodbump.PushStage, Err: errors.New("push failed")}}},
+			exitCodeRepositoriesFailed,
+		},
+		{"should exit with 64 for an invalid usage", fmt.Errorf("%w: bump requires a repository", errUsage), exitCodeUsage},
+		{"should exit with 1 for another error", errors.New("loading state failed"), exitCodeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			exitCode := getExitCode(tt.err)
+			if exitCode != tt.wantExitCode {
+				t.Errorf("got '%v' want '%v'", exitCode, tt.wantExitCode)
+			}
+		})
+	}
+}
+
+func writeConfig(t *testing.T, dir, name, config string) string {
+	path := filepath.Join(dir, name)
+
+	err := ioutil.WriteFile(path, []byte(config), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
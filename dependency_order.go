@@ -0,0 +1,167 @@
+package gomodbump
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/depgraph"
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
+// goModFilename is the go.mod file at the root of a repository.
+const goModFilename = "go.mod"
+
+// dependencyOrder makes each repository wait for the repositories providing the modules it requires, so
+// downstream repositories are bumped after their upstream repositories. A nil order does not wait.
+type dependencyOrder struct {
+	mu      sync.Mutex
+	modules map[string]*bump.Module
+	cloned  sync.WaitGroup
+
+	graphOnce sync.Once
+	graph     *depgraph.Graph
+
+	done    map[string]chan struct{}
+	pending map[string]string
+}
+
+func newDependencyOrder(repos repository.Repositories) *dependencyOrder {
+	o := &dependencyOrder{
+		modules: make(map[string]*bump.Module, len(repos)),
+		done:    make(map[string]chan struct{}, len(repos)),
+		pending: make(map[string]string, len(repos)),
+	}
+
+	for n := range repos {
+		o.done[repos[n].Name] = make(chan struct{})
+	}
+
+	o.cloned.Add(len(repos))
+
+	return o
+}
+
+// setModule records the Go module of the repository once it was cloned, nil if it is not a Go module or it could
+// not be cloned. It has to be called once for every repository.
+func (o *dependencyOrder) setModule(name string, module *bump.Module) {
+	if o == nil {
+		return
+	}
+
+	if module != nil {
+		o.mu.Lock()
+		o.modules[name] = module
+		o.mu.Unlock()
+	}
+
+	o.cloned.Done()
+}
+
+// wait blocks until every repository was cloned and the upstream repositories of the repository were processed.
+// It returns what the repository is waiting for, or an empty string if its upstream repositories are released.
+func (o *dependencyOrder) wait(ctx context.Context, name string) (string, error) {
+	if o == nil {
+		return "", nil
+	}
+
+	o.cloned.Wait()
+	o.graphOnce.Do(o.buildGraph)
+
+	upstreams := o.graph.Upstreams(name)
+
+	for _, upstream := range upstreams {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-o.done[upstream]:
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, upstream := range upstreams {
+		if pending := o.pending[upstream]; pending != "" {
+			return pending, nil
+		}
+	}
+
+	return "", nil
+}
+
+// finish marks the repository as processed, its downstream repositories wait for what is pending, if anything.
+func (o *dependencyOrder) finish(name, pending string) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	select {
+	case <-o.done[name]:
+		return
+	default:
+	}
+
+	o.pending[name] = pending
+	close(o.done[name])
+}
+
+func (o *dependencyOrder) buildGraph() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.graph = depgraph.New(o.modules)
+
+	for _, cycle := range o.graph.Cycles() {
+		log.Printf("repo '%s' and repo '%s' require each other's modules, repo '%s' is not bumped after repo '%s'", cycle[0], cycle[1], cycle[0], cycle[1])
+	}
+}
+
+// readModule returns the Go module of the cloned repository, or nil if it is not a Go module.
+func readModule(ctx context.Context, repo *repository.Repository) (*bump.Module, error) {
+	if !repo.Cloned || repo.GitRepo == nil {
+		return nil, nil
+	}
+
+	_, err := os.Stat(filepath.Join(repo.ClonePath(), goModFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return bump.ReadModule(ctx, repo.ClonePath())
+}
+
+// getPendingRelease returns what the downstream repositories of the processed repository have to wait for before
+// being bumped: its pull request to be merged or its merged changes to be tagged. An empty string is returned
+// if the latest changes to its go.mod file are released.
+func (b *GoModBump) getPendingRelease(repo *repository.Repository, repoPlan *RepositoryPlan) string {
+	switch {
+	case repoPlan.WaitingFor != "":
+		return repoPlan.WaitingFor
+	case repo.PullRequestOpened || repoPlan.Push || repoPlan.PullRequest:
+		return fmt.Sprintf("pull request of repo '%s' to be merged", repo.Name)
+	case repoPlan.Merge:
+		return fmt.Sprintf("repo '%s' to be tagged", repo.Name)
+	case !repo.Cloned || repo.GitRepo == nil:
+		return ""
+	}
+
+	released, err := b.vcsManager.IsReleased(repo, goModFilename)
+	if err != nil {
+		log.Printf("repo '%s': unable to check if it is released, not waiting for it: %s", repo.Name, err)
+		return ""
+	}
+
+	if !released {
+		return fmt.Sprintf("repo '%s' to be tagged", repo.Name)
+	}
+
+	return ""
+}
@@ -0,0 +1,266 @@
+// nolint:scopelint
+package gomodbump_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump"
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
+func TestDependencyOrderWait(t *testing.T) {
+	lib := &bump.Module{Path: "git.acme.com/lib"}
+	api := &bump.Module{Path: "git.acme.com/api", Requires: []string{"git.acme.com/lib"}}
+	cyclicLib := &bump.Module{Path: "git.acme.com/lib", Requires: []string{"git.acme.com/api"}}
+
+	var tests = []struct {
+		testName    string
+		modules     map[string]*bump.Module
+		finished    map[string]string
+		cancelled   bool
+		wait        string
+		wantPending string
+		wantErr     error
+	}{
+		{
+			"should return what the upstream repository is pending",
+			map[string]*bump.Module{"api": api, "lib": lib},
+			map[string]string{"lib": "pull request of repo 'lib' to be merged"},
+			false,
+			"api",
+			"pull request of repo 'lib' to be merged",
+			nil,
+		},
+		{
+			"should not wait for an upstream repository that is released",
+			map[string]*bump.Module{"api": api, "lib": lib},
+			map[string]string{"lib": ""},
+			false,
+			"api",
+			"",
+			nil,
+		},
+		{
+			"should not wait for a repository without upstream repositories",
+			map[string]*bump.Module{"api": api, "lib": lib},
+			map[string]string{},
+			false,
+			"lib",
+			"",
+			nil,
+		},
+		{
+			"should not wait for an upstream repository that failed to clone",
+			map[string]*bump.Module{"api": api, "lib": nil},
+			map[string]string{},
+			false,
+			"api",
+			"",
+			nil,
+		},
+		{
+			"should ignore the requirement forming a cycle",
+			map[string]*bump.Module{"api": api, "lib": cyclicLib},
+			map[string]string{},
+			false,
+			"lib",
+			"",
+			nil,
+		},
+		{
+			"should still wait for the upstream repository of a cycle",
+			map[string]*bump.Module{"api": api, "lib": cyclicLib},
+			map[string]string{"lib": "repo 'lib' to be tagged"},
+			false,
+			"api",
+			"repo 'lib' to be tagged",
+			nil,
+		},
+		{
+			"should stop waiting when cancelled",
+			map[string]*bump.Module{"api": api, "lib": lib},
+			map[string]string{},
+			true,
+			"api",
+			"",
+			context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			repos := repository.Repositories{}
+			for name := range tt.modules {
+				repos = append(repos, repository.NewRepository(name, "", "acme", repository.BitbucketServer, repository.Git))
+			}
+
+			order := gomodbump.NewDependencyOrder(repos)
+
+			for name, module := range tt.modules {
+				order.SetModule(name, module)
+			}
+
+			for name, pending := range tt.finished {
+				order.Finish(name, pending)
+			}
+
+			// A repository that is waited for when it should not be makes the wait time out.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if tt.cancelled {
+				cancel()
+			}
+
+			pending, err := order.Wait(ctx, tt.wait)
+			if err != tt.wantErr {
+				t.Fatalf("got error '%v' want '%v'", err, tt.wantErr)
+			}
+
+			if pending != tt.wantPending {
+				t.Errorf("got '%v' want '%v'", pending, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestDependencyOrderWaitForUpstream(t *testing.T) {
+	repos := repository.Repositories{
+		repository.NewRepository("api", "", "acme", repository.BitbucketServer, repository.Git),
+		repository.NewRepository("lib", "", "acme", repository.BitbucketServer, repository.Git),
+	}
+
+	order := gomodbump.NewDependencyOrder(repos)
+	order.SetModule("api", &bump.Module{Path: "git.acme.com/api", Requires: []string{"git.acme.com/lib"}})
+	order.SetModule("lib", &bump.Module{Path: "git.acme.com/lib"})
+
+	waited := make(chan string, 1)
+
+	go func() {
+		pending, _ := order.Wait(context.Background(), "api")
+		waited <- pending
+	}()
+
+	select {
+	case pending := <-waited:
+		t.Fatalf("got '%v' before the upstream repository finished want to wait for it", pending)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	order.Finish("lib", "repo 'lib' to be tagged")
+
+	// Finishing a repository again does not change what is pending.
+	order.Finish("lib", "")
+
+	select {
+	case pending := <-waited:
+		if pending != "repo 'lib' to be tagged" {
+			t.Errorf("got '%v' want '%v'", pending, "repo 'lib' to be tagged")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("got no return want the wait to return once the upstream repository finished")
+	}
+}
+
+func TestGetPendingRelease(t *testing.T) {
+	dir := t.TempDir()
+
+	// The go.mod file of the released origin is the one at its latest tag, it changed since in the unreleased one.
+	released := newOrigin(t, filepath.Join(dir, "released"), newGoMod("git.acme.com/released", "v1.0.0"))
+	runGit(t, released, "tag", "v1.0.0")
+
+	unreleased := newOrigin(t, filepath.Join(dir, "unreleased"), newGoMod("git.acme.com/unreleased", "v1.0.0"))
+	runGit(t, unreleased, "tag", "v1.0.0")
+	writeGoMod(t, unreleased, newGoMod("git.acme.com/unreleased", "v1.1.0"))
+	runGit(t, unreleased, "commit", "-q", "-am", "bump")
+
+	var tests = []struct {
+		testName    string
+		origin      string
+		cloned      bool
+		opened      bool
+		repoPlan    *gomodbump.RepositoryPlan
+		wantPending string
+	}{
+		{
+			"should pass on what the repository is waiting for",
+			released,
+			true,
+			false,
+			&gomodbump.RepositoryPlan{WaitingFor: "repo 'lib' to be tagged"},
+			"repo 'lib' to be tagged",
+		},
+		{
+			"should wait for the pull request that is open",
+			released,
+			true,
+			true,
+			&gomodbump.RepositoryPlan{},
+			"pull request of repo 'api' to be merged",
+		},
+		{
+			"should wait for the pull request that was created",
+			released,
+			true,
+			false,
+			&gomodbump.RepositoryPlan{Push: true, PullRequest: true},
+			"pull request of repo 'api' to be merged",
+		},
+		{
+			"should wait for the merged pull request to be tagged",
+			released,
+			true,
+			false,
+			&gomodbump.RepositoryPlan{Merge: true},
+			"repo 'api' to be tagged",
+		},
+		{
+			"should not wait for a repository that failed to clone",
+			released,
+			false,
+			false,
+			&gomodbump.RepositoryPlan{},
+			"",
+		},
+		{
+			"should not wait for a repository that is released",
+			released,
+			true,
+			false,
+			&gomodbump.RepositoryPlan{},
+			"",
+		},
+		{
+			"should wait for the changes to the go.mod file to be tagged",
+			unreleased,
+			true,
+			false,
+			&gomodbump.RepositoryPlan{},
+			"repo 'api' to be tagged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			repo := repository.NewRepository("api", tt.origin, "acme", repository.BitbucketServer, repository.Git)
+			repo.BaseDir = t.TempDir()
+			repo.TargetBranch = "master"
+			repo.PullRequestOpened = tt.opened
+
+			if tt.cloned {
+				cloneOrigin(t, repo)
+			}
+
+			gmb := newGoModBump(t, newConfiguration(dir), &fakeSCM{}, &fakeBumper{}, &fakeStorage{})
+
+			pending := gmb.GetPendingRelease(repo, tt.repoPlan)
+			if pending != tt.wantPending {
+				t.Errorf("got '%v' want '%v'", pending, tt.wantPending)
+			}
+		})
+	}
+}
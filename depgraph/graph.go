@@ -0,0 +1,92 @@
+package depgraph
+
+import (
+	"sort"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+// Graph is the dependencies between repositories through the Go modules they provide and require. A
+// repository requiring the module of another repository is downstream of it.
+type Graph struct {
+	upstreams map[string][]string
+	order     []string
+	cycles    [][2]string
+}
+
+// New builds the graph from the Go module of each repository, by repository name. A requirement that would
+// form a cycle is ignored so every repository can be processed after its upstreams.
+func New(modules map[string]*bump.Module) *Graph {
+	repoByModule := make(map[string]string, len(modules))
+	names := make([]string, 0, len(modules))
+
+	for name, module := range modules {
+		repoByModule[module.Path] = name
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	g := &Graph{upstreams: make(map[string][]string, len(modules))}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(modules))
+
+	var visit func(name string)
+
+	visit = func(name string) {
+		state[name] = visiting
+
+		requires := append([]string{}, modules[name].Requires...)
+		sort.Strings(requires)
+
+		for _, require := range requires {
+			upstream, ok := repoByModule[require]
+			if !ok || upstream == name {
+				continue
+			}
+
+			switch state[upstream] {
+			case visiting:
+				g.cycles = append(g.cycles, [2]string{name, upstream})
+				continue
+			case unvisited:
+				visit(upstream)
+			}
+
+			g.upstreams[name] = append(g.upstreams[name], upstream)
+		}
+
+		state[name] = visited
+		g.order = append(g.order, name)
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return g
+}
+
+// Upstreams returns the names of the repositories providing the modules the repository requires.
+func (g *Graph) Upstreams(name string) []string {
+	return g.upstreams[name]
+}
+
+// Order returns the names of the repositories with every repository after its upstreams.
+func (g *Graph) Order() []string {
+	return g.order
+}
+
+// Cycles returns the requirements that were ignored because they form a cycle, as pairs of the downstream and
+// upstream repository names.
+func (g *Graph) Cycles() [][2]string {
+	return g.cycles
+}
@@ -0,0 +1,73 @@
+// nolint:scopelint
+package depgraph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/depgraph"
+)
+
+func TestGraph(t *testing.T) {
+	var tests = []struct {
+		testName      string
+		modules       map[string]*bump.Module
+		wantOrder     []string
+		wantUpstreams map[string][]string
+		wantCycles    [][2]string
+	}{
+		{
+			"should order repositories after the repositories they require",
+			map[string]*bump.Module{
+				"api":     {Path: "git.acme.com/api", Requires: []string{"git.acme.com/lib", "golang.org/x/sync"}},
+				"lib":     {Path: "git.acme.com/lib", Requires: []string{"golang.org/x/sync"}},
+				"service": {Path: "git.acme.com/service", Requires: []string{"git.acme.com/api", "git.acme.com/lib"}},
+			},
+			[]string{"lib", "api", "service"},
+			map[string][]string{"api": {"lib"}, "lib": nil, "service": {"api", "lib"}},
+			nil,
+		},
+		{
+			"should not order independent repositories",
+			map[string]*bump.Module{
+				"b": {Path: "git.acme.com/b"},
+				"a": {Path: "git.acme.com/a"},
+			},
+			[]string{"a", "b"},
+			map[string][]string{"a": nil, "b": nil},
+			nil,
+		},
+		{
+			"should ignore a requirement forming a cycle",
+			map[string]*bump.Module{
+				"a": {Path: "git.acme.com/a", Requires: []string{"git.acme.com/b"}},
+				"b": {Path: "git.acme.com/b", Requires: []string{"git.acme.com/a"}},
+				"c": {Path: "git.acme.com/c", Requires: []string{"git.acme.com/a"}},
+			},
+			[]string{"b", "a", "c"},
+			map[string][]string{"a": {"b"}, "b": nil, "c": {"a"}},
+			[][2]string{{"b", "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			graph := depgraph.New(tt.modules)
+
+			if got := graph.Order(); !reflect.DeepEqual(got, tt.wantOrder) {
+				t.Errorf("got order %v want %v", got, tt.wantOrder)
+			}
+
+			for name, want := range tt.wantUpstreams {
+				if got := graph.Upstreams(name); !reflect.DeepEqual(got, want) {
+					t.Errorf("got upstreams %v of %s want %v", got, name, want)
+				}
+			}
+
+			if got := graph.Cycles(); !reflect.DeepEqual(got, tt.wantCycles) {
+				t.Errorf("got cycles %v want %v", got, tt.wantCycles)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package gomodbump
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/ratelimit"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/webhook"
+	"golang.org/x/sync/semaphore"
+)
+
+// NewGoModBumpWith initializes a Go Mod Bump with the managers provided instead of the ones of the configuration,
+// for the gomodbump_test package.
+func NewGoModBumpWith(conf Configuration, scmManager scmManager, vcsManager vcsManager, bumper bumper, storageManager storageManager) *GoModBump {
+	return &GoModBump{
+		conf:           conf,
+		scmManager:     scmManager,
+		vcsManager:     vcsManager,
+		bumper:         bumper,
+		storageManager: storageManager,
+		rateLimiters:   newRateLimiters(conf.General.RateLimits, conf.General.Delay, conf.General.Workers),
+		running:        semaphore.NewWeighted(1),
+	}
+}
+
+// WaitForLimit exposes waitForLimit to the gomodbump_test package with a worker of its own.
+func WaitForLimit(ctx context.Context, limiter *ratelimit.Limiter, checkWindow func() error) error {
+	return waitForLimit(ctx, &worker{sem: semaphore.NewWeighted(1)}, limiter, checkWindow)
+}
+
+// Scheduler exposes scheduler to the gomodbump_test package.
+type Scheduler = scheduler
+
+// NewScheduler exposes newScheduler to the gomodbump_test package, the current time is returned by now.
+func NewScheduler(conf ServeConfig, now func() time.Time) (*Scheduler, error) {
+	sched, err := newScheduler(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	sched.now = now
+
+	return sched, nil
+}
+
+// GetDue exposes getDue to the gomodbump_test package.
+func (s *scheduler) GetDue(repos repository.Repositories, now time.Time) repository.Repositories {
+	return s.getDue(repos, now)
+}
+
+// Update exposes update to the gomodbump_test package.
+func (s *scheduler) Update(plan *Plan, runErr error, now time.Time) {
+	s.update(plan, runErr, now)
+}
+
+// ServeSchedule exposes serveSchedule to the gomodbump_test package.
+func (b *GoModBump) ServeSchedule(ctx context.Context, sched *Scheduler) error {
+	return b.serveSchedule(ctx, sched)
+}
+
+// RunTagged exposes runTagged to the gomodbump_test package.
+func (b *GoModBump) RunTagged(ctx context.Context, event *webhook.TagEvent) {
+	b.runTagged(ctx, event)
+}
+
+// GetTaggedModule exposes getTaggedModule to the gomodbump_test package.
+func (b *GoModBump) GetTaggedModule(ctx context.Context, event *webhook.TagEvent) (string, error) {
+	return b.getTaggedModule(ctx, event)
+}
+
+// DependencyOrder exposes dependencyOrder to the gomodbump_test package.
+type DependencyOrder = dependencyOrder
+
+// NewDependencyOrder exposes newDependencyOrder to the gomodbump_test package.
+var NewDependencyOrder = newDependencyOrder
+
+// SetModule exposes setModule to the gomodbump_test package.
+func (o *dependencyOrder) SetModule(name string, module *bump.Module) {
+	o.setModule(name, module)
+}
+
+// Wait exposes wait to the gomodbump_test package.
+func (o *dependencyOrder) Wait(ctx context.Context, name string) (string, error) {
+	return o.wait(ctx, name)
+}
+
+// Finish exposes finish to the gomodbump_test package.
+func (o *dependencyOrder) Finish(name, pending string) {
+	o.finish(name, pending)
+}
+
+// GetPendingRelease exposes getPendingRelease to the gomodbump_test package.
+func (b *GoModBump) GetPendingRelease(repo *repository.Repository, repoPlan *RepositoryPlan) string {
+	return b.getPendingRelease(repo, repoPlan)
+}
+
+// LoadRepositoryConfig exposes loadRepositoryConfig to the gomodbump_test package.
+var LoadRepositoryConfig = loadRepositoryConfig
+
+// ApplyRepositoryConfig exposes applyRepositoryConfig to the gomodbump_test package.
+func (b *GoModBump) ApplyRepositoryConfig(ctx context.Context, repo *repository.Repository) (bump.Configuration, bool, error) {
+	return b.applyRepositoryConfig(ctx, repo)
+}
@@ -0,0 +1,80 @@
+package gomodbump
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ryancurrah/gomodbump/bump"
+)
+
+// ErrRepositoriesFailed is returned when processing one or more repositories failed.
+var ErrRepositoriesFailed = errors.New("processing repositories failed")
+
+// Stage is the step of processing a repository.
+type Stage string
+
+var (
+	// CloneStage clones the repository.
+	CloneStage Stage = "clone"
+	// MergeStage merges the open pull request and deletes its branch.
+	MergeStage Stage = "merge"
+	// ConfigureStage loads the repository's .gomodbump.yaml file.
+	ConfigureStage Stage = "configure"
+	// BumpStage finds and updates the modules.
+	BumpStage Stage = "bump"
+	// PushStage commits and pushes the changes.
+	PushStage Stage = "push"
+	// PullRequestStage creates the pull request.
+	PullRequestStage Stage = "pull request"
+)
+
+// RepositoryFailure is a repository that failed to be processed.
+type RepositoryFailure struct {
+	Repository string
+	Stage      Stage
+	Err        error
+}
+
+// isDiscoveryFailure returns true if only finding the module updates failed.
+func (f *RepositoryFailure) isDiscoveryFailure() bool {
+	var discoveryErr *bump.DiscoveryError
+
+	return f.Stage == BumpStage && errors.As(f.Err, &discoveryErr)
+}
+
+func (f *RepositoryFailure) String() string {
+	var discoveryErr *bump.DiscoveryError
+	if f.isDiscoveryFailure() && errors.As(f.Err, &discoveryErr) {
+		return fmt.Sprintf("%s (%s %s)", f.Repository, f.Stage, discoveryErr.Kind)
+	}
+
+	return fmt.Sprintf("%s (%s)", f.Repository, f.Stage)
+}
+
+// RunError is the summary of the repositories that failed to be processed during a run. It is
+// ErrUpdateDiscoveryFailed when only finding module updates failed and ErrRepositoriesFailed otherwise.
+type RunError struct {
+	Failures []*RepositoryFailure
+}
+
+func (e *RunError) Error() string {
+	failures := make([]string, 0, len(e.Failures))
+
+	for _, failure := range e.Failures {
+		failures = append(failures, failure.String())
+	}
+
+	return fmt.Sprintf("%s for %d repos: %s", e.Unwrap(), len(e.Failures), strings.Join(failures, ", "))
+}
+
+// Unwrap returns the kind of failure.
+func (e *RunError) Unwrap() error {
+	for _, failure := range e.Failures {
+		if !failure.isDiscoveryFailure() {
+			return ErrRepositoriesFailed
+		}
+	}
+
+	return ErrUpdateDiscoveryFailed
+}
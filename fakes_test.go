@@ -0,0 +1,323 @@
+// nolint:scopelint
+package gomodbump_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/ryancurrah/gomodbump"
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/vcs"
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+// fakeSCM lists the repositories and merges pull requests by merging their source branch into the checked out
+// branch of the origin repository with git.
+type fakeSCM struct {
+	mu           sync.Mutex
+	repos        []*repository.Repository
+	listed       int
+	merged       []string
+	pullRequests []string
+}
+
+func (s *fakeSCM) SCMType() repository.SCM {
+	return repository.BitbucketServer
+}
+
+func (s *fakeSCM) GetRepositories(ctx context.Context, vcsType repository.VCS) (repository.Repositories, error) {
+	s.mu.Lock()
+	s.listed++
+	s.mu.Unlock()
+
+	repos := make(repository.Repositories, len(s.repos))
+
+	for n, repo := range s.repos {
+		repos[n] = repository.NewRepository(repo.Name, repo.URL, repo.Parent, repo.SCM, repo.VCS)
+	}
+
+	return repos, nil
+}
+
+func (s *fakeSCM) MergePullRequest(ctx context.Context, repo *repository.Repository) error {
+	output, err := exec.Command("git", "-C", repo.URL, "merge", "-q", repo.SourceBranch).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("unable to merge %s: %s: %s", repo.SourceBranch, output, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.merged = append(s.merged, repo.Name)
+
+	return nil
+}
+
+func (s *fakeSCM) CreatePullRequest(ctx context.Context, repo *repository.Repository) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pullRequests = append(s.pullRequests, repo.Name)
+
+	return len(s.pullRequests), nil
+}
+
+func (s *fakeSCM) getListed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.listed
+}
+
+func (s *fakeSCM) getMerged() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.merged...)
+}
+
+func (s *fakeSCM) getPullRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.pullRequests...)
+}
+
+// fakeBumper updates git.acme.com/lib to v1.2.0 in the go.mod file of the repositories requiring it and records
+// the go.mod file each of them had and the configuration. The onBump function, if set, is called before each
+// repository is bumped, which fails if its context was cancelled by then.
+type fakeBumper struct {
+	mu     sync.Mutex
+	goMods map[string]string
+	confs  map[string]bump.Configuration
+	onBump func(ctx context.Context)
+}
+
+func (b *fakeBumper) Bump(ctx context.Context, repo *repository.Repository, conf bump.Configuration) (*repository.BumpResult, error) {
+	if b.onBump != nil {
+		b.onBump(ctx)
+	}
+
+	goModPath := filepath.Join(repo.ClonePath(), "go.mod")
+
+	goMod, err := ioutil.ReadFile(goModPath)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	if b.goMods == nil {
+		b.goMods = make(map[string]string)
+		b.confs = make(map[string]bump.Configuration)
+	}
+
+	b.goMods[repo.Name] = string(goMod)
+	b.confs[repo.Name] = conf
+	b.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	oldRequire := strings.Fields(string(goMod))
+	oldVersion := ""
+
+	for n := range oldRequire {
+		if oldRequire[n] == "git.acme.com/lib" && n+1 < len(oldRequire) {
+			oldVersion = oldRequire[n+1]
+		}
+	}
+
+	if oldVersion == "" || oldVersion == "v1.2.0" {
+		return nil, nil
+	}
+
+	err = ioutil.WriteFile(goModPath, []byte(strings.Replace(string(goMod), "git.acme.com/lib "+oldVersion, "git.acme.com/lib v1.2.0", 1)), 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	old, _ := version.Parse(oldVersion)
+	latest, _ := version.Parse("v1.2.0")
+
+	return &repository.BumpResult{
+		Updates: repository.Updates{{Module: "git.acme.com/lib", OldVersion: old, NewVersion: latest, Kind: repository.DirectUpdate}},
+	}, nil
+}
+
+func (b *fakeBumper) getGoMod(name string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.goMods[name]
+}
+
+func (b *fakeBumper) getConf(name string) bump.Configuration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.confs[name]
+}
+
+// fakeStorage keeps the repositories in memory. The onSave function, if set, is called after they are saved.
+type fakeStorage struct {
+	mu     sync.Mutex
+	repos  repository.Repositories
+	onSave func()
+}
+
+func (s *fakeStorage) Save(ctx context.Context, repos repository.Repositories) error {
+	s.mu.Lock()
+	s.repos = repos
+	s.mu.Unlock()
+
+	if s.onSave != nil {
+		s.onSave()
+	}
+
+	return nil
+}
+
+// Load returns copies of the repositories, like reading them from a file, so a run does not change them.
+func (s *fakeStorage) Load(ctx context.Context) (repository.Repositories, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repos := make(repository.Repositories, len(s.repos))
+
+	for n := range s.repos {
+		repo := *s.repos[n]
+		repos[n] = &repo
+	}
+
+	return repos, nil
+}
+
+// fakeClock is the current time of the scheduler.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+// newConfiguration returns the configuration of a stateful run with its work dir in the directory.
+func newConfiguration(dir string) gomodbump.Configuration {
+	return gomodbump.Configuration{General: gomodbump.GeneralConfig{Workers: 2, WorkDir: filepath.Join(dir, "work"), Stateful: true, ForbidRepositoryConfig: true}}
+}
+
+// newGoModBump returns a Go Mod Bump cloning the origin repositories with git into the work dir.
+func newGoModBump(t *testing.T, conf gomodbump.Configuration, scm *fakeSCM, bumper *fakeBumper, storage *fakeStorage) *gomodbump.GoModBump {
+	gitVCS, err := vcs.NewGit(vcs.GitConfig{TargetBranch: "master", SourceBranch: "gomodbump", CommitMessage: "bump", CommitAuthorName: "gomodbump", CommitAuthorEmail: "gomodbump@acme.com"}, "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gomodbump.NewGoModBumpWith(conf, scm, gitVCS, bumper, storage)
+}
+
+// newOrigin creates a repository with the go.mod file committed to its master branch to clone from.
+func newOrigin(t *testing.T, dir, goMod string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeGoMod(t, dir, goMod)
+
+	runGit(t, dir, "init", "-q", "-b", "master")
+	runGit(t, dir, "config", "receive.denyCurrentBranch", "ignore")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "initial commit")
+
+	return dir
+}
+
+// commitBranch commits the go.mod file to a new branch of the origin repository, master stays checked out.
+func commitBranch(t *testing.T, dir, branch, goMod string) {
+	runGit(t, dir, "checkout", "-q", "-b", branch)
+	writeGoMod(t, dir, goMod)
+	runGit(t, dir, "commit", "-q", "-am", "bump")
+	runGit(t, dir, "checkout", "-q", "master")
+}
+
+// cloneOrigin clones the target branch of the origin repository into the clone path of the repository.
+func cloneOrigin(t *testing.T, repo *repository.Repository) {
+	output, err := exec.Command("git", "clone", "-q", "-b", repo.TargetBranch, repo.URL, repo.ClonePath()).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git clone failed: %s: %s", output, err)
+	}
+
+	gitRepo, err := git.PlainOpen(repo.ClonePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.SetCloned(gitRepo)
+}
+
+// getBranches returns the branches of the origin repository.
+func getBranches(t *testing.T, dir string) []string {
+	output, err := exec.Command("git", "-C", dir, "branch", "--format=%(refname:short)").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch failed: %s: %s", output, err)
+	}
+
+	return strings.Fields(string(output))
+}
+
+// readGoMod returns the go.mod file at the branch of the origin repository.
+func readGoMod(t *testing.T, dir, branch string) string {
+	output, err := exec.Command("git", "-C", dir, "show", branch+":go.mod").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git show failed: %s: %s", output, err)
+	}
+
+	return string(output)
+}
+
+func writeGoMod(t *testing.T, dir, goMod string) {
+	err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@acme.com"}, args...)
+
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %s: %s", strings.Join(args, " "), output, err)
+	}
+}
+
+func newGoMod(module, libVersion string) string {
+	return fmt.Sprintf("module %s\n\ngo 1.16\n\nrequire git.acme.com/lib %s\n", module, libVersion)
+}
@@ -2,14 +2,19 @@ package gomodbump
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/ryancurrah/gomodbump/bump"
 	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/retry"
 	"github.com/ryancurrah/gomodbump/scm"
 	"github.com/ryancurrah/gomodbump/storage"
 	"github.com/ryancurrah/gomodbump/vcs"
@@ -17,29 +22,51 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
+// ErrRepositoryNotFound is returned when the repository to bump does not exist in the SCM.
+var ErrRepositoryNotFound = errors.New("repository not found")
+
+// ErrInterrupted is returned when the run was cancelled before all the repositories were processed.
+var ErrInterrupted = errors.New("run interrupted")
+
+// ErrUpdateDiscoveryFailed is returned when finding module updates failed for one or more repositories.
+var ErrUpdateDiscoveryFailed = errors.New("update discovery failed")
+
+// ErrOutsideWindow is returned when a repository is not merged or pushed because the maintenance window ended.
+var ErrOutsideWindow = errors.New("outside of the maintenance window")
+
+// ErrorLog logs the repositories and the runs that failed. It is separate from the standard logger so the failures
+// are still logged when the other messages are discarded.
+var ErrorLog = log.New(os.Stderr, "", log.LstdFlags)
+
+// defaultShutdownTimeout is lower than the default Kubernetes termination grace period so the state can be saved.
+const defaultShutdownTimeout = 20 * time.Second
+
 type scmManager interface {
 	SCMType() repository.SCM
-	GetRepositories(vcsType repository.VCS) (repository.Repositories, error)
-	MergePullRequest(repo *repository.Repository) error
-	CreatePullRequest(repo *repository.Repository) (int, error)
+	GetRepositories(ctx context.Context, vcsType repository.VCS) (repository.Repositories, error)
+	MergePullRequest(ctx context.Context, repo *repository.Repository) error
+	CreatePullRequest(ctx context.Context, repo *repository.Repository) (int, error)
 }
 
 type vcsManager interface {
 	GetSourceBranch() string
 	GetTargetBranch() string
 	VCSType() repository.VCS
-	Clone(repo *repository.Repository) (*git.Repository, error)
-	Push(repo *repository.Repository) error
-	DeleteBranch(repo *repository.Repository) error
+	Clone(ctx context.Context, repo *repository.Repository) (*git.Repository, error)
+	Push(ctx context.Context, repo *repository.Repository) error
+	DeleteBranch(ctx context.Context, repo *repository.Repository) error
+	ReadFile(ctx context.Context, url, tag, filename string) ([]byte, error)
+	ReadBranchFile(ctx context.Context, url, branch, filename string) ([]byte, error)
+	IsReleased(repo *repository.Repository, filename string) (bool, error)
 }
 
 type bumper interface {
-	Bump(repo *repository.Repository) (repository.Updates, error)
+	Bump(ctx context.Context, repo *repository.Repository, conf bump.Configuration) (*repository.BumpResult, error)
 }
 
 type storageManager interface {
-	Save(repos repository.Repositories) error
-	Load() (repository.Repositories, error)
+	Save(ctx context.Context, repos repository.Repositories) error
+	Load(ctx context.Context) (repository.Repositories, error)
 }
 
 // GeneralConfig are general settings for this package.
@@ -50,6 +77,22 @@ type GeneralConfig struct {
 	Stateful  bool          `yaml:"stateful"`
 	Cleanup   bool          `yaml:"cleanup"`
 	Delay     time.Duration `yaml:"delay"`
+
+	// RateLimits limit how often repositories are merged, pushed and have pull requests created.
+	RateLimits RateLimitsConfig `yaml:"rate_limits"`
+
+	// Retry is how SCM and VCS operations that failed with a transient error are retried.
+	Retry retry.Config `yaml:"retry"`
+
+	// DependencyOrder bumps the repositories requiring the modules of other repositories after them. A repository
+	// is not bumped while the repositories it requires have a pull request open or changes that are not tagged.
+	DependencyOrder bool `yaml:"dependency_order"`
+
+	// ShutdownTimeout is how long the repositories being processed have to finish once the run is interrupted.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// ForbidRepositoryConfig ignores the .gomodbump.yaml file of the repositories.
+	ForbidRepositoryConfig bool `yaml:"forbid_repository_config"`
 }
 
 // SourceCodeManagementConfig used to create pull requests and get repos.
@@ -76,6 +119,55 @@ type Configuration struct {
 	VCS     VersionControlSystemConfig `yaml:"vcs"`
 	Bump    bump.Configuration         `yaml:"bump"`
 	Storage StorageConfig              `yaml:"storage"`
+	Serve   ServeConfig                `yaml:"serve"`
+}
+
+// Validate returns an error if any of the settings are invalid.
+func (c Configuration) Validate() error {
+	if c.General.Workers < 1 {
+		return fmt.Errorf("invalid workers %d, at least 1 worker is required", c.General.Workers)
+	}
+
+	switch c.General.CloneType {
+	case "", "http", "ssh":
+	default:
+		return fmt.Errorf("invalid clone_type '%s', expected http or ssh", c.General.CloneType)
+	}
+
+	if c.General.WorkDir == "" {
+		return errors.New("work_dir is required")
+	}
+
+	if c.General.ShutdownTimeout < 0 {
+		return fmt.Errorf("invalid shutdown_timeout %v, it can not be negative", c.General.ShutdownTimeout)
+	}
+
+	err := c.General.Retry.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = c.General.RateLimits.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = c.Serve.Validate()
+	if err != nil {
+		return err
+	}
+
+	return c.Bump.Validate()
+}
+
+// GetShutdownTimeout returns how long the repositories being processed have to finish once the run is
+// interrupted.
+func (c GeneralConfig) GetShutdownTimeout() time.Duration {
+	if c.ShutdownTimeout == 0 {
+		return defaultShutdownTimeout
+	}
+
+	return c.ShutdownTimeout
 }
 
 // GetWorkDir returns the working dir path cleaned.
@@ -90,167 +182,613 @@ type GoModBump struct {
 	vcsManager     vcsManager
 	bumper         bumper
 	storageManager storageManager
+	rateLimiters   rateLimiters
+
+	// running prevents runs from overlapping when serving.
+	running *semaphore.Weighted
 }
 
 // NewGoModBump initializes a Go Mod Bump struct.
 func NewGoModBump(conf Configuration) (*GoModBump, error) {
-	vcsManager, err := vcs.NewGit(conf.VCS.Git, conf.General.CloneType)
+	err := conf.Validate()
 	if err != nil {
 		return nil, err
 	}
 
-	var storageManager storageManager
+	vcsManager, err := vcs.NewGit(conf.VCS.Git, conf.General.CloneType)
+	if err != nil {
+		return nil, err
+	}
 
-	if conf.Storage.S3 != (storage.S3StorageConfig{}) {
-		var errStorage error
+	storageManager, err := newStorageManager(conf.Storage)
+	if err != nil {
+		return nil, err
+	}
 
-		storageManager, errStorage = storage.NewS3Storage(conf.Storage.S3)
-		if errStorage != nil {
-			return nil, errStorage
-		}
-	} else {
-		storageManager = storage.NewFileStorage(conf.Storage.File)
+	bumper, err := bump.NewBumper(conf.Bump)
+	if err != nil {
+		return nil, err
 	}
 
+	scmManager := scm.NewBitbucketServer(conf.SCM.PullRequest, conf.SCM.BitbucketServer, conf.General.CloneType)
+
 	return &GoModBump{
 		conf:           conf,
-		scmManager:     scm.NewBitbucketServer(conf.SCM.PullRequest, conf.SCM.BitbucketServer, conf.General.CloneType),
-		vcsManager:     vcsManager,
-		bumper:         bump.NewBumper(conf.Bump),
+		scmManager:     &retryingSCMManager{scmManager: scmManager, conf: conf.General.Retry},
+		vcsManager:     &retryingVCSManager{vcsManager: vcsManager, conf: conf.General.Retry},
+		bumper:         bumper,
 		storageManager: storageManager,
+		rateLimiters:   newRateLimiters(conf.General.RateLimits, conf.General.Delay, conf.General.Workers),
+		running:        semaphore.NewWeighted(1),
 	}, nil
 }
 
-// Run Go Mod Bump.
-func (b *GoModBump) Run() error { // nolint:gocognit
-	ctx := context.Background()
+// Status returns the repositories saved in the storage, these have an open pull request.
+func Status(ctx context.Context, conf Configuration) (repository.Repositories, error) {
+	storageManager, err := newStorageManager(conf.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return storageManager.Load(ctx)
+}
+
+// BumpLocal bumps the Go module in a directory that is already checked out with the bump configuration, it is
+// not cloned, pushed or has a pull request created. The repository's .gomodbump.yaml file is applied but not
+// its schedule or target branch.
+func BumpLocal(ctx context.Context, conf Configuration, dir string) (*RepositoryPlan, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	bumper, err := bump.NewBumper(conf.Bump)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := repository.NewLocalRepository(dir)
+	repoPlan := &RepositoryPlan{Repository: repo.Name}
+
+	bumpConf := conf.Bump
+
+	if !conf.General.ForbidRepositoryConfig {
+		repoConf, err := loadRepositoryConfig(repo)
+		if err != nil {
+			return nil, err
+		}
+
+		bumpConf = bumpConf.Merge(repoConf.RepositoryConfig)
+	}
 
+	result, err := bumper.Bump(ctx, repo, bumpConf)
+	if err != nil || result == nil {
+		return repoPlan, err
+	}
+
+	repoPlan.Updates = result.Updates
+	repoPlan.GoVersion = result.GoVersion
+	repoPlan.Replacements = result.Replacements
+	repoPlan.Deprecations = result.Deprecations
+	repoPlan.Violations = result.Violations
+
+	return repoPlan, nil
+}
+
+func newStorageManager(conf StorageConfig) (storageManager, error) {
+	if conf.S3 != (storage.S3StorageConfig{}) {
+		return storage.NewS3Storage(conf.S3)
+	}
+
+	return storage.NewFileStorage(conf.File), nil
+}
+
+// Run Go Mod Bump. When the context is cancelled no more repositories are started, the repositories being
+// processed have until the shutdown timeout to finish and the state is saved.
+func (b *GoModBump) Run(ctx context.Context) error {
+	_, err := b.run(ctx, runOptions{})
+	return err
+}
+
+// RunRepository runs Go Mod Bump for a single repository of the SCM.
+func (b *GoModBump) RunRepository(ctx context.Context, name string) error {
+	_, err := b.run(ctx, runOptions{filter: func(repos repository.Repositories) (repository.Repositories, error) {
+		repos = repos.GetByName(name)
+		if len(repos) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrRepositoryNotFound, name)
+		}
+
+		return repos, nil
+	}})
+
+	return err
+}
+
+// Plan finds what Go Mod Bump would do without pushing, merging or creating pull requests and without
+// saving the state. The repositories are still cloned and bumped in the work dir.
+func (b *GoModBump) Plan(ctx context.Context) (*Plan, error) {
+	return b.run(ctx, runOptions{dryRun: true})
+}
+
+// repositoryFilter returns the repositories of the SCM to process.
+type repositoryFilter func(repos repository.Repositories) (repository.Repositories, error)
+
+// runOptions change what a run does.
+type runOptions struct {
+	// dryRun does not merge, push or create pull requests and does not save the state.
+	dryRun bool
+
+	// filter returns the repositories to process, all of them are processed when it is nil.
+	filter repositoryFilter
+
+	// module only bumps this module and only in the repositories requiring it, without merging the open pull
+	// requests, when it is set.
+	module string
+
+	// inWindow returns false once the repositories must no longer be merged or pushed, they always can be when it
+	// is nil.
+	inWindow func() (bool, error)
+}
+
+// checkWindow returns ErrOutsideWindow if the repositories must no longer be merged or pushed.
+func (o runOptions) checkWindow() error {
+	if o.inWindow == nil {
+		return nil
+	}
+
+	inWindow, err := o.inWindow()
+	if err != nil {
+		return err
+	}
+
+	if !inWindow {
+		return ErrOutsideWindow
+	}
+
+	return nil
+}
+
+// run processes the repositories of the SCM.
+func (b *GoModBump) run(ctx context.Context, opts runOptions) (*Plan, error) {
 	// Cleanup working directory before running.
 	b.clean()
 
 	// Get the repos from the last the run, this contains PR info.
-	reposFromStorage, err := b.storageManager.Load()
+	reposFromStorage, err := b.storageManager.Load(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get current repos from SCM.
-	reposFromSCM, err := b.scmManager.GetRepositories(b.vcsManager.VCSType())
+	reposFromSCM, err := b.scmManager.GetRepositories(ctx, b.vcsManager.VCSType())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Converge the repos from storage into the repos from SCM.
-	repos := converge(b.conf.GetWorkDir(), reposFromStorage, reposFromSCM)
+	allRepos := converge(b.conf.GetWorkDir(), reposFromStorage, reposFromSCM)
+	repos := allRepos
+
+	if opts.filter != nil {
+		repos, err = opts.filter(allRepos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The repositories being processed are only cancelled once the shutdown timeout passed after the run
+	// was interrupted, so they are not left half merged or pushed without a pull request.
+	workCtx, cancelWork := withShutdownTimeout(ctx, b.conf.General.GetShutdownTimeout())
+	defer cancelWork()
 
 	sem := semaphore.NewWeighted(int64(b.conf.General.Workers))
 
-	group, ctx := errgroup.WithContext(ctx)
+	var (
+		failuresMu sync.Mutex
+		failures   []*RepositoryFailure
+	)
+
+	plan := &Plan{Repositories: make([]*RepositoryPlan, len(repos))}
+
+	fail := func(repo *repository.Repository, stage Stage, err error) {
+		ErrorLog.Printf("repo '%s': %s failed: %s", repo.Name, stage, err)
+
+		failuresMu.Lock()
+		failures = append(failures, &RepositoryFailure{Repository: repo.Name, Stage: stage, Err: err})
+		failuresMu.Unlock()
+	}
+
+	// Every repository is cloned before any is bumped when ordering them by dependencies, the order is built from
+	// their go.mod files.
+	var order *dependencyOrder
+	if b.conf.General.DependencyOrder {
+		order = newDependencyOrder(repos)
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
 
 	for n := range repos {
+		n := n
 		repo := repos[n]
 
 		group.Go(func() error {
-			err := sem.Acquire(ctx, 1)
+			w := &worker{sem: sem}
+			defer w.release()
+
+			// The repositories downstream of this one are not blocked when it fails.
+			var pending string
+			defer func() { order.finish(repo.Name, pending) }()
+
+			err := w.acquire(groupCtx)
 			if err != nil {
+				order.setModule(repo.Name, nil)
 				return err
 			}
-			defer sem.Release(1)
 
-			// Clone repos locally.
-			if repo.IsCloneable(b.vcsManager.VCSType()) {
-				vcsRepoClient, err := b.vcsManager.Clone(repo)
+			// Keep processing the other repos when one fails, the failures are reported once all are done.
+			err = b.cloneRepository(workCtx, repo)
+			if err != nil {
+				order.setModule(repo.Name, nil)
+				plan.Repositories[n] = &RepositoryPlan{Repository: repo.Name}
+				fail(repo, CloneStage, err)
+
+				return nil
+			}
+
+			var waitingFor string
+
+			if order != nil {
+				module, err := readModule(workCtx, repo)
 				if err != nil {
-					return err
+					log.Printf("repo '%s': unable to read module, it is not ordered: %s", repo.Name, err)
 				}
 
-				repo.SetCloned(vcsRepoClient)
-			}
+				order.setModule(repo.Name, module)
 
-			// If any of the repos have a pull request open and they are mergeable, merge them (If auto_merge=true).
-			if repo.IsMergeable(b.scmManager.SCMType()) {
-				err = b.scmManager.MergePullRequest(repo)
+				// The worker is given back while waiting for the upstream repositories.
+				w.release()
+
+				waitingFor, err = order.wait(groupCtx, repo.Name)
 				if err != nil {
 					return err
 				}
 
-				err = b.vcsManager.DeleteBranch(repo)
+				err = w.acquire(groupCtx)
 				if err != nil {
 					return err
 				}
+			}
 
-				repo.ResetState()
+			repoPlan, stage, err := b.processRepository(workCtx, w, repo, opts, waitingFor)
 
-				log.Printf("repo '%s': merged pull request and sleeping for %v", repo.Name, b.conf.General.Delay)
+			plan.Repositories[n] = repoPlan
 
-				b.sleep()
+			if err != nil {
+				fail(repo, stage, err)
 			}
 
-			// Find and update Go module dependencies.
-			if repo.IsBumpable() {
-				updates, err := b.bumper.Bump(repo)
-				if err != nil {
-					return err
-				}
+			if order != nil {
+				pending = b.getPendingRelease(repo, repoPlan)
+			}
 
-				if updates == nil {
-					return nil
-				}
+			return nil
+		})
+	}
 
-				repo.SetBumped(updates)
-			}
+	errWait := group.Wait()
 
-			// Push repos to remote, includes committing.
-			if repo.IsPushable(b.vcsManager.VCSType()) {
-				err = b.vcsManager.Push(repo)
-				if err != nil {
-					return err
-				}
+	if b.conf.General.Cleanup {
+		defer b.clean()
+	}
 
-				repo.SetPushed()
+	// Only save repos to storage where a PR was created and Stateful or Auto Merge is set to true. The state
+	// is saved even when the run was interrupted. The repos that were filtered out keep their state.
+	if !opts.dryRun && (b.conf.General.Stateful || b.conf.SCM.PullRequest.AutoMerge) {
+		saveCtx, cancelSave := context.WithTimeout(context.Background(), b.conf.General.GetShutdownTimeout())
+		defer cancelSave()
 
-				log.Printf("repo '%s': pushed and sleeping for %v", repo.Name, b.conf.General.Delay)
+		err = b.storageManager.Save(saveCtx, allRepos.GetSavable())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return plan, ErrInterrupted
+	}
 
-				b.sleep()
+	if errWait != nil {
+		return nil, errWait
+	}
+
+	if len(failures) > 0 {
+		sort.Slice(failures, func(i, j int) bool { return failures[i].Repository < failures[j].Repository })
+
+		return plan, &RunError{Failures: failures}
+	}
+
+	return plan, nil
+}
+
+// cloneRepository clones the repository locally if it uses the VCS.
+func (b *GoModBump) cloneRepository(ctx context.Context, repo *repository.Repository) error {
+	if !repo.IsCloneable(b.vcsManager.VCSType()) {
+		return nil
+	}
+
+	vcsRepoClient, err := b.vcsManager.Clone(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	repo.SetCloned(vcsRepoClient)
+
+	return nil
+}
+
+// processRepository merges, bumps, pushes and creates a pull request for the cloned repository depending on
+// its state and returns what was done. The stage that failed is returned with the error. Nothing is merged,
+// pushed or created in a dry run. The repository is not bumped while it is waiting for an upstream repository.
+func (b *GoModBump) processRepository(ctx context.Context, w *worker, repo *repository.Repository, opts runOptions, waitingFor string) (*RepositoryPlan, Stage, error) {
+	repoPlan := &RepositoryPlan{Repository: repo.Name}
+
+	// If any of the repos have a pull request open and they are mergeable, merge them (If auto_merge=true). A
+	// targeted bump does not merge, the pull requests are merged by the scheduled runs.
+	if opts.module == "" && repo.IsMergeable(b.scmManager.SCMType()) {
+		repoPlan.Merge = true
+		repoPlan.PullRequestID = repo.PullRequestID
+		repoPlan.MergeBranch = repo.SourceBranch
+
+		if !opts.dryRun {
+			err := b.mergePullRequest(ctx, w, repo, opts)
+			if err != nil {
+				return repoPlan, MergeStage, err
 			}
 
-			// Create pull requests for repos where they are PRable.
-			if repo.IsPRable(b.scmManager.SCMType()) {
-				pullRequestID, err := b.scmManager.CreatePullRequest(repo)
-				if err != nil {
-					return err
-				}
+			// The repository is bumped from the merged changes by the next run.
+			repo.ResetState()
 
-				repo.SetPullRequest(int64(pullRequestID))
+			return repoPlan, "", nil
+		}
 
-				log.Printf("repo '%s': created pull request and sleeping for %v", repo.Name, b.conf.General.Delay)
+		// The bump of the next run is planned on the source branch of the pull request, it is what the target
+		// branch would be once merged.
+		err := b.recloneRepository(ctx, repo, repo.SourceBranch)
+		if err != nil {
+			return repoPlan, CloneStage, err
+		}
+	}
+
+	// Find and update Go module dependencies.
+	if repo.IsBumpable() {
+		if waitingFor != "" {
+			log.Printf("repo '%s': waiting for %s, skipping", repo.Name, waitingFor)
+
+			repoPlan.WaitingFor = waitingFor
 
-				b.sleep()
+			return repoPlan, "", nil
+		}
+
+		if opts.module != "" {
+			module, err := bump.ReadModule(ctx, repo.ClonePath())
+			if err != nil {
+				return repoPlan, BumpStage, err
 			}
 
-			return nil
-		})
+			if !module.HasRequire(opts.module) {
+				log.Printf("repo '%s': does not require %s, skipping", repo.Name, opts.module)
+
+				return repoPlan, "", nil
+			}
+		}
+
+		bumpConf, inSchedule, err := b.applyRepositoryConfig(ctx, repo)
+		if err != nil {
+			return repoPlan, ConfigureStage, err
+		}
+
+		if !inSchedule {
+			log.Printf("repo '%s': outside of the repository's schedule, skipping", repo.Name)
+
+			return repoPlan, "", nil
+		}
+
+		if opts.module != "" {
+			bumpConf = bumpConf.Target(opts.module)
+		}
+
+		result, err := b.bumper.Bump(ctx, repo, bumpConf)
+		if err != nil {
+			return repoPlan, BumpStage, err
+		}
+
+		if result == nil {
+			return repoPlan, "", nil
+		}
+
+		repo.SetBumped(result)
+
+		repoPlan.Updates = result.Updates
+		repoPlan.GoVersion = result.GoVersion
+		repoPlan.Replacements = result.Replacements
+		repoPlan.Deprecations = result.Deprecations
+		repoPlan.Violations = result.Violations
 	}
 
-	errWait := group.Wait()
+	// Push repos to remote, includes committing.
+	if repo.IsPushable(b.vcsManager.VCSType()) {
+		repoPlan.Push = true
+		repoPlan.SourceBranch = repo.SourceBranch
+		repoPlan.TargetBranch = repo.TargetBranch
 
-	if b.conf.General.Cleanup {
-		defer b.clean()
+		if opts.dryRun {
+			// The pull request would be created once the branch is pushed.
+			repoPlan.PullRequest = true
+			repoPlan.PullRequestTitle = b.conf.SCM.PullRequest.GetTitle(repo)
+			repoPlan.Reviewers = b.conf.SCM.PullRequest.GetReviewers(repo)
+
+			return repoPlan, "", nil
+		}
+
+		// The pull request of a pushed branch is always created, so the window is only checked before pushing.
+		err := waitForLimit(ctx, w, b.rateLimiters.push, opts.checkWindow)
+		if err != nil {
+			return repoPlan, PushStage, err
+		}
+
+		err = b.vcsManager.Push(ctx, repo)
+		if err != nil {
+			return repoPlan, PushStage, err
+		}
+
+		repo.SetPushed()
+
+		log.Printf("repo '%s': pushed", repo.Name)
 	}
 
-	// Only save repos to storage where a PR was created and Stateful or Auto Merge is set to true.
-	if b.conf.General.Stateful || b.conf.SCM.PullRequest.AutoMerge {
-		err = b.storageManager.Save(repos.GetSavable())
+	// Create pull requests for repos where they are PRable.
+	if repo.IsPRable(b.scmManager.SCMType()) {
+		repoPlan.PullRequest = true
+		repoPlan.PullRequestTitle = b.conf.SCM.PullRequest.GetTitle(repo)
+		repoPlan.Reviewers = b.conf.SCM.PullRequest.GetReviewers(repo)
+		repoPlan.SourceBranch = repo.SourceBranch
+		repoPlan.TargetBranch = repo.TargetBranch
+
+		if opts.dryRun {
+			return repoPlan, "", nil
+		}
+
+		err := waitForLimit(ctx, w, b.rateLimiters.pullRequest, nil)
 		if err != nil {
-			return err
+			return repoPlan, PullRequestStage, err
 		}
+
+		pullRequestID, err := b.scmManager.CreatePullRequest(ctx, repo)
+		if err != nil {
+			return repoPlan, PullRequestStage, err
+		}
+
+		repo.SetPullRequest(int64(pullRequestID))
+
+		log.Printf("repo '%s': created pull request #%d", repo.Name, pullRequestID)
 	}
 
-	return errWait
+	return repoPlan, "", nil
 }
 
-func (b *GoModBump) sleep() {
-	time.Sleep(b.conf.General.Delay)
+// mergePullRequest merges the open pull request of the repository and deletes its source branch.
+func (b *GoModBump) mergePullRequest(ctx context.Context, w *worker, repo *repository.Repository, opts runOptions) error {
+	err := waitForLimit(ctx, w, b.rateLimiters.merge, opts.checkWindow)
+	if err != nil {
+		return err
+	}
+
+	err = b.scmManager.MergePullRequest(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	err = b.vcsManager.DeleteBranch(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("repo '%s': merged pull request", repo.Name)
+
+	return nil
+}
+
+// recloneRepository resets the state of the repository and clones the branch instead of its target branch, e.g.
+// to plan the bump from the changes of its pull request. The repository keeps its target branch.
+func (b *GoModBump) recloneRepository(ctx context.Context, repo *repository.Repository, branch string) error {
+	targetBranch := repo.TargetBranch
+
+	repo.ResetState()
+
+	err := os.RemoveAll(repo.ClonePath())
+	if err != nil {
+		return fmt.Errorf("repo '%s': unable to remove clone: %s", repo.Name, err)
+	}
+
+	repo.TargetBranch = branch
+
+	err = b.cloneRepository(ctx, repo)
+
+	repo.TargetBranch = targetBranch
+
+	return err
+}
+
+// applyRepositoryConfig loads the repository's .gomodbump.yaml file and returns the bump configuration merged
+// with it and false if the repository should not be bumped now because of its schedule. The repository is
+// cloned again if it sets a different target branch and the .gomodbump.yaml file of that branch is used instead.
+func (b *GoModBump) applyRepositoryConfig(ctx context.Context, repo *repository.Repository) (bump.Configuration, bool, error) {
+	if b.conf.General.ForbidRepositoryConfig {
+		return b.conf.Bump, true, nil
+	}
+
+	repoConf, err := loadRepositoryConfig(repo)
+	if err != nil {
+		return bump.Configuration{}, false, err
+	}
+
+	if repoConf.TargetBranch != "" && repoConf.TargetBranch != repo.TargetBranch {
+		log.Printf("repo '%s': cloning target branch %s from %s", repo.Name, repoConf.TargetBranch, repositoryConfigFilename)
+
+		repo.TargetBranch = repoConf.TargetBranch
+
+		err = os.RemoveAll(repo.ClonePath())
+		if err != nil {
+			return bump.Configuration{}, false, fmt.Errorf("repo '%s': unable to remove clone: %s", repo.Name, err)
+		}
+
+		vcsRepoClient, err := b.vcsManager.Clone(ctx, repo)
+		if err != nil {
+			return bump.Configuration{}, false, err
+		}
+
+		repo.SetCloned(vcsRepoClient)
+
+		// The target branch of the target branch's file is not followed, the repository is only cloned once.
+		repoConf, err = loadRepositoryConfig(repo)
+		if err != nil {
+			return bump.Configuration{}, false, err
+		}
+	}
+
+	inSchedule, err := repoConf.Schedule.Contains(time.Now())
+	if err != nil || !inSchedule {
+		return bump.Configuration{}, false, err
+	}
+
+	repo.Reviewers = repoConf.Reviewers
+
+	return b.conf.Bump.Merge(repoConf.RepositoryConfig), true, nil
+}
+
+// withShutdownTimeout returns a context that is cancelled once the timeout passed after the parent context
+// was cancelled.
+func withShutdownTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		select {
+		case <-parent.Done():
+		case <-ctx.Done():
+			return
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
 }
 
 func (b *GoModBump) clean() {
@@ -2,9 +2,18 @@
 package gomodbump_test
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ryancurrah/gomodbump"
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/version"
 )
 
 func TestConfigurationGetWorkDir(t *testing.T) {
@@ -34,3 +43,292 @@ func TestConfigurationGetWorkDir(t *testing.T) {
 		})
 	}
 }
+
+func TestRunError(t *testing.T) {
+	discoveryFailure := &gomodbump.RepositoryFailure{
+		Repository: "api",
+		Stage:      gomodbump.BumpStage,
+		Err:        bump.NewDiscoveryError("dial tcp: lookup proxy.example.com: no such host", errors.New("exit status 1")),
+	}
+	pushFailure := &gomodbump.RepositoryFailure{
+		Repository: "web",
+		Stage:      gomodbump.PushStage,
+		Err:        errors.New("authentication required"),
+	}
+
+	var tests = []struct {
+		testName  string
+		failures  []*gomodbump.RepositoryFailure
+		wantErr   error
+		wantError string
+	}{
+		{
+			"should be a discovery failure when only discovery failed",
+			[]*gomodbump.RepositoryFailure{discoveryFailure},
+			gomodbump.ErrUpdateDiscoveryFailed,
+			"update discovery failed for 1 repos: api (bump network)",
+		},
+		{
+			"should be a repositories failure when any other stage failed",
+			[]*gomodbump.RepositoryFailure{discoveryFailure, pushFailure},
+			gomodbump.ErrRepositoriesFailed,
+			"processing repositories failed for 2 repos: api (bump network), web (push)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := &gomodbump.RunError{Failures: tt.failures}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got '%v' want '%v'", errors.Unwrap(err), tt.wantErr)
+			}
+
+			if err.Error() != tt.wantError {
+				t.Errorf("got '%v' want '%v'", err.Error(), tt.wantError)
+			}
+		})
+	}
+}
+
+func TestPlanPrint(t *testing.T) {
+	oldVersion, _ := version.Parse("v1.0.0")
+	newVersion, _ := version.Parse("v1.1.0")
+
+	plan := &gomodbump.Plan{
+		Repositories: []*gomodbump.RepositoryPlan{
+			{Repository: "web"},
+			{Repository: "service", WaitingFor: "pull request of repo 'api' to be merged"},
+			{
+				Repository:       "db",
+				Merge:            true,
+				PullRequestID:    7,
+				MergeBranch:      "updating-go-modules-20200408",
+				Updates:          repository.Updates{{Module: "github.com/acme/lib", OldVersion: oldVersion, NewVersion: newVersion, Kind: repository.DirectUpdate}},
+				Push:             true,
+				SourceBranch:     "updating-go-modules-20200415",
+				TargetBranch:     "master",
+				PullRequest:      true,
+				PullRequestTitle: "Updating go.mod dependencies",
+			},
+			{
+				Repository: "api",
+				Updates:    repository.Updates{{Module: "github.com/acme/lib", OldVersion: oldVersion, NewVersion: newVersion, Kind: repository.DirectUpdate}},
+				Replacements: repository.Replacements{
+					{Module: "github.com/acme/log", Replacement: "github.com/fork/log", ReplacementVersion: "v1.0.0", UpstreamVersion: "v1.2.0"},
+					{Module: "github.com/acme/db", Replacement: "../db"},
+					{Module: "github.com/acme/http", Replacement: "github.com/acme/http", ReplacementVersion: "v1.3.0", Advisories: repository.Advisories{{ID: "GO-2024-0001", Severity: "HIGH"}}},
+				},
+				Push:             true,
+				SourceBranch:     "updating-go-modules-20200415",
+				TargetBranch:     "master",
+				PullRequest:      true,
+				PullRequestTitle: "Updating go.mod dependencies",
+			},
+		},
+	}
+
+	want := `2 of 4 repos would be changed
+
+api
+  update github.com/acme/lib v1.0.0 -> v1.1.0 (direct)
+  replaced github.com/acme/log => github.com/fork/log v1.0.0 (fork is behind upstream v1.2.0)
+  replaced github.com/acme/db => ../db (local directory)
+  replaced github.com/acme/http => github.com/acme/http v1.3.0 (vulnerable to GO-2024-0001 HIGH)
+  push branch updating-go-modules-20200415
+  create pull request 'Updating go.mod dependencies' from updating-go-modules-20200415 to master
+
+db
+  merge pull request #7 and delete branch updating-go-modules-20200408
+  update github.com/acme/lib v1.0.0 -> v1.1.0 (direct)
+  push branch updating-go-modules-20200415
+  create pull request 'Updating go.mod dependencies' from updating-go-modules-20200415 to master
+
+service
+  waiting for pull request of repo 'api' to be merged
+`
+
+	output := &strings.Builder{}
+
+	plan.Print(output)
+
+	if output.String() != want {
+		t.Errorf("got '%v' want '%v'", output.String(), want)
+	}
+}
+
+func TestGoModBumpMerge(t *testing.T) {
+	dir := t.TempDir()
+
+	origin := newOrigin(t, filepath.Join(dir, "origin"), newGoMod("git.acme.com/api", "v1.0.0"))
+	commitBranch(t, origin, "pr", newGoMod("git.acme.com/api", "v1.1.0"))
+
+	scm := &fakeSCM{repos: []*repository.Repository{repository.NewRepository("api", origin, "acme", repository.BitbucketServer, repository.Git)}}
+
+	storedRepo := repository.NewRepository("api", origin, "acme", repository.BitbucketServer, repository.Git)
+	storedRepo.PullRequestOpened = true
+	storedRepo.PullRequestID = 7
+	storedRepo.SourceBranch = "pr"
+	storedRepo.TargetBranch = "master"
+
+	storage := &fakeStorage{repos: repository.Repositories{storedRepo}}
+
+	// The dry run plans the merge and the bump of the next run from the changes of the pull request.
+	bumper := &fakeBumper{}
+
+	plan, err := newGoModBump(t, newConfiguration(dir), scm, bumper, storage).Plan(context.Background())
+	if err != nil {
+		t.Fatalf("got '%v' want '%v'", err, nil)
+	}
+
+	wantGoMod := newGoMod("git.acme.com/api", "v1.1.0")
+	if goMod := bumper.getGoMod("api"); goMod != wantGoMod {
+		t.Errorf("got planned go.mod '%v' want '%v'", goMod, wantGoMod)
+	}
+
+	repoPlan := plan.Repositories[0]
+
+	if !repoPlan.Merge || repoPlan.MergeBranch != "pr" || repoPlan.PullRequestID != 7 {
+		t.Errorf("got merge '%v' from '%v' #%d want '%v' from '%v' #%d", repoPlan.Merge, repoPlan.MergeBranch, repoPlan.PullRequestID, true, "pr", 7)
+	}
+
+	if len(repoPlan.Updates) != 1 || !repoPlan.Push || !repoPlan.PullRequest || repoPlan.TargetBranch != "master" {
+		t.Errorf("got updates '%v' push '%v' pull request '%v' to '%v' want a bump pushed to a pull request to '%v'", repoPlan.Updates, repoPlan.Push, repoPlan.PullRequest, repoPlan.TargetBranch, "master")
+	}
+
+	if merged := scm.getMerged(); len(merged) != 0 {
+		t.Errorf("got merged '%v' want none", merged)
+	}
+
+	// The first run merges the pull request and deletes its source branch without bumping.
+	bumper = &fakeBumper{}
+
+	err = newGoModBump(t, newConfiguration(dir), scm, bumper, storage).Run(context.Background())
+	if err != nil {
+		t.Fatalf("got '%v' want '%v'", err, nil)
+	}
+
+	if goMod := bumper.getGoMod("api"); goMod != "" {
+		t.Errorf("got bumped go.mod '%v' want no bump until the next run", goMod)
+	}
+
+	if merged := scm.getMerged(); !reflect.DeepEqual(merged, []string{"api"}) {
+		t.Errorf("got merged '%v' want '%v'", merged, []string{"api"})
+	}
+
+	if goMod := readGoMod(t, origin, "master"); goMod != wantGoMod {
+		t.Errorf("got target go.mod '%v' want '%v'", goMod, wantGoMod)
+	}
+
+	if branches := getBranches(t, origin); !reflect.DeepEqual(branches, []string{"master"}) {
+		t.Errorf("got branches '%v' want '%v'", branches, []string{"master"})
+	}
+
+	if pullRequests := scm.getPullRequests(); len(pullRequests) != 0 {
+		t.Errorf("got pull requests '%v' want none", pullRequests)
+	}
+
+	// The next run bumps the merged changes and pushes them to a new pull request.
+	err = newGoModBump(t, newConfiguration(dir), scm, bumper, storage).Run(context.Background())
+	if err != nil {
+		t.Fatalf("got '%v' want '%v'", err, nil)
+	}
+
+	if goMod := bumper.getGoMod("api"); goMod != wantGoMod {
+		t.Errorf("got bumped go.mod '%v' want '%v'", goMod, wantGoMod)
+	}
+
+	if pullRequests := scm.getPullRequests(); !reflect.DeepEqual(pullRequests, []string{"api"}) {
+		t.Errorf("got pull requests '%v' want '%v'", pullRequests, []string{"api"})
+	}
+
+	branches := getBranches(t, origin)
+	if len(branches) != 2 || branches[1] != "master" {
+		t.Fatalf("got branches '%v' want a new source branch and '%v'", branches, "master")
+	}
+
+	wantGoMod = newGoMod("git.acme.com/api", "v1.2.0")
+	if goMod := readGoMod(t, origin, branches[0]); goMod != wantGoMod {
+		t.Errorf("got pushed go.mod '%v' want '%v'", goMod, wantGoMod)
+	}
+}
+
+func TestGoModBumpRunInterrupted(t *testing.T) {
+	var tests = []struct {
+		testName         string
+		shutdownTimeout  time.Duration
+		blockBump        bool
+		wantPullRequests int
+	}{
+		{"should finish the repository being processed and save its state", time.Minute, false, 1},
+		{"should cancel the repository being processed once the shutdown timeout passed", 10 * time.Millisecond, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := t.TempDir()
+
+			scm := &fakeSCM{}
+
+			for _, name := range []string{"api", "lib", "web"} {
+				origin := newOrigin(t, filepath.Join(dir, name), newGoMod("git.acme.com/"+name, "v1.0.0"))
+				scm.repos = append(scm.repos, repository.NewRepository(name, origin, "acme", repository.BitbucketServer, repository.Git))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// The run is interrupted while the first repository is bumped.
+			bumper := &fakeBumper{onBump: func(bumpCtx context.Context) {
+				cancel()
+
+				if tt.blockBump {
+					<-bumpCtx.Done()
+				}
+			}}
+
+			storage := &fakeStorage{}
+
+			conf := newConfiguration(dir)
+			conf.General.Workers = 1
+			conf.General.ShutdownTimeout = tt.shutdownTimeout
+
+			err := newGoModBump(t, conf, scm, bumper, storage).Run(ctx)
+			if !errors.Is(err, gomodbump.ErrInterrupted) {
+				t.Fatalf("got '%v' want '%v'", err, gomodbump.ErrInterrupted)
+			}
+
+			bumped := []string{}
+
+			for _, repo := range scm.repos {
+				if bumper.getGoMod(repo.Name) != "" {
+					bumped = append(bumped, repo.Name)
+				}
+			}
+
+			if len(bumped) != 1 {
+				t.Fatalf("got bumped '%v' want only the first repository", bumped)
+			}
+
+			pullRequests := scm.getPullRequests()
+			if len(pullRequests) != tt.wantPullRequests {
+				t.Errorf("got pull requests '%v' want %d", pullRequests, tt.wantPullRequests)
+			}
+
+			savedRepos, err := storage.Load(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			saved := []string{}
+
+			for _, repo := range savedRepos {
+				saved = append(saved, repo.Name)
+			}
+
+			if !reflect.DeepEqual(saved, pullRequests) {
+				t.Errorf("got saved '%v' want '%v'", saved, pullRequests)
+			}
+		})
+	}
+}
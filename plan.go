@@ -0,0 +1,147 @@
+package gomodbump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
+// Plan is what a run would do to the repositories, it is the result of a dry run.
+type Plan struct {
+	Repositories []*RepositoryPlan
+}
+
+// RepositoryPlan is what a run would do to a repository.
+type RepositoryPlan struct {
+	Repository string
+
+	// Merge is true if the open pull request would be merged and its source branch deleted. The repository is
+	// bumped from the merged changes by the next run, that bump is planned from the source branch.
+	Merge         bool
+	PullRequestID int64
+	MergeBranch   string
+
+	Updates      repository.Updates
+	GoVersion    *repository.GoVersionUpdate
+	Replacements repository.Replacements
+	Deprecations repository.Deprecations
+	Violations   repository.Violations
+
+	// Push is true if the updates would be committed and pushed to the source branch.
+	Push         bool
+	SourceBranch string
+	TargetBranch string
+
+	// PullRequest is true if a pull request would be created from the source branch to the target branch.
+	PullRequest      bool
+	PullRequestTitle string
+	Reviewers        []string
+
+	// WaitingFor is why the repository would not be bumped, an upstream repository it requires is not released.
+	WaitingFor string `json:",omitempty"`
+}
+
+// hasChanges returns true if the repository would be changed.
+func (p *RepositoryPlan) hasChanges() bool {
+	return p.Merge || p.Push || p.PullRequest
+}
+
+// WriteJSON writes the plan to the file as JSON.
+func (p *Plan) WriteJSON(filename string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644) // nolint: gosec
+}
+
+// Print writes a summary of the plan.
+func (p *Plan) Print(w io.Writer) {
+	repos := make([]*RepositoryPlan, 0, len(p.Repositories))
+	changed := 0
+
+	for n := range p.Repositories {
+		if p.Repositories[n].hasChanges() {
+			changed++
+		}
+
+		if p.Repositories[n].hasChanges() || p.Repositories[n].WaitingFor != "" {
+			repos = append(repos, p.Repositories[n])
+		}
+	}
+
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Repository < repos[j].Repository })
+
+	fmt.Fprintf(w, "%d of %d repos would be changed\n", changed, len(p.Repositories))
+
+	for _, repo := range repos {
+		fmt.Fprintln(w)
+		repo.Print(w)
+	}
+}
+
+// Print writes a summary of what would be done to the repository.
+func (p *RepositoryPlan) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s\n", p.Repository)
+
+	if p.Merge {
+		fmt.Fprintf(w, "  merge pull request #%d and delete branch %s\n", p.PullRequestID, p.MergeBranch)
+	}
+
+	if p.WaitingFor != "" {
+		fmt.Fprintf(w, "  waiting for %s\n", p.WaitingFor)
+	}
+
+	if p.GoVersion != nil {
+		fmt.Fprintf(w, "  go %s -> %s\n", p.GoVersion.OldGo, p.GoVersion.NewGo)
+	}
+
+	for _, update := range p.Updates {
+		fmt.Fprintf(w, "  update %s %s -> %s (%s)\n", update.Module, update.OldVersion, update.NewVersion, update.Kind)
+	}
+
+	for _, replacement := range p.Replacements {
+		fmt.Fprintf(w, "  replaced %s\n", formatReplacement(replacement))
+	}
+
+	for _, deprecation := range p.Deprecations {
+		fmt.Fprintf(w, "  deprecated %s: %s\n", deprecation.Module, deprecation.Message)
+	}
+
+	for _, violation := range p.Violations {
+		fmt.Fprintf(w, "  violation %s %s: %s\n", violation.Module, violation.Version, violation.Reason)
+	}
+
+	if p.Push {
+		fmt.Fprintf(w, "  push branch %s\n", p.SourceBranch)
+	}
+
+	if p.PullRequest {
+		fmt.Fprintf(w, "  create pull request '%s' from %s to %s\n", p.PullRequestTitle, p.SourceBranch, p.TargetBranch)
+	}
+}
+
+// formatReplacement returns the replace directive and what is wrong with it, if anything.
+func formatReplacement(replacement *repository.Replacement) string {
+	s := fmt.Sprintf("%s => %s", replacement.Module, replacement.Replacement)
+
+	switch {
+	case replacement.IsLocal():
+		s += " (local directory)"
+	case replacement.IsBehindUpstream():
+		s += fmt.Sprintf(" %s (fork is behind upstream %s)", replacement.ReplacementVersion, replacement.UpstreamVersion)
+	default:
+		s += " " + replacement.ReplacementVersion
+	}
+
+	for _, advisory := range replacement.Advisories {
+		s += fmt.Sprintf(" (vulnerable to %s %s)", advisory.ID, advisory.Severity)
+	}
+
+	return s
+}
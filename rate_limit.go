@@ -0,0 +1,122 @@
+package gomodbump
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/ratelimit"
+	"golang.org/x/sync/semaphore"
+)
+
+// RateLimitsConfig limits how often each action is done across all the workers, e.g. at most 20 pull requests
+// per hour no matter how many workers there are.
+type RateLimitsConfig struct {
+	Merges       ratelimit.Config `yaml:"merges"`
+	Pushes       ratelimit.Config `yaml:"pushes"`
+	PullRequests ratelimit.Config `yaml:"pull_requests"`
+}
+
+// Validate returns an error if any of the rate limits are invalid.
+func (c RateLimitsConfig) Validate() error {
+	for name, limit := range map[string]ratelimit.Config{"merges": c.Merges, "pushes": c.Pushes, "pull_requests": c.PullRequests} {
+		err := limit.Validate()
+		if err != nil {
+			return fmt.Errorf("rate_limits %s: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// rateLimiters are the limiters of each action.
+type rateLimiters struct {
+	merge       *ratelimit.Limiter
+	push        *ratelimit.Limiter
+	pullRequest *ratelimit.Limiter
+}
+
+// newRateLimiters returns the limiters of the actions. An action without a rate limit is done at most once
+// per delay per worker, if there is a delay, like the workers sleeping for the delay after each action did.
+func newRateLimiters(conf RateLimitsConfig, delay time.Duration, workers int) rateLimiters {
+	withDelay := func(limit ratelimit.Config) ratelimit.Config {
+		if limit.IsEnabled() || delay <= 0 {
+			return limit
+		}
+
+		return ratelimit.Config{Count: workers, Per: delay}
+	}
+
+	return rateLimiters{
+		merge:       ratelimit.New(withDelay(conf.Merges)),
+		push:        ratelimit.New(withDelay(conf.Pushes)),
+		pullRequest: ratelimit.New(withDelay(conf.PullRequests)),
+	}
+}
+
+// worker is a slot of the worker pool used to process a repository.
+type worker struct {
+	sem      *semaphore.Weighted
+	acquired bool
+}
+
+func (w *worker) acquire(ctx context.Context) error {
+	// The semaphore can still be acquired once the context is cancelled, no repository is started after the run
+	// was interrupted.
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	err := w.sem.Acquire(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	w.acquired = true
+
+	return nil
+}
+
+func (w *worker) release() {
+	if w.acquired {
+		w.sem.Release(1)
+		w.acquired = false
+	}
+}
+
+// waitForLimit waits until the action is allowed by its rate limit. The worker is given back while waiting so
+// other repositories can be cloned and bumped in the meantime. The window, if there is one, is checked before the
+// action takes its token and again after waiting, the token is given back if the action is not done.
+func waitForLimit(ctx context.Context, w *worker, limiter *ratelimit.Limiter, checkWindow func() error) error {
+	if checkWindow == nil {
+		checkWindow = func() error { return nil }
+	}
+
+	err := checkWindow()
+	if err != nil {
+		return err
+	}
+
+	if limiter.Allow() {
+		return nil
+	}
+
+	w.release()
+
+	err = limiter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = checkWindow()
+	if err == nil {
+		err = w.acquire(ctx)
+	}
+
+	if err != nil {
+		limiter.Release()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// nolint:scopelint
+package gomodbump_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump"
+	"github.com/ryancurrah/gomodbump/ratelimit"
+)
+
+func TestWaitForLimit(t *testing.T) {
+	var tests = []struct {
+		testName      string
+		tokenTaken    bool
+		windowEnds    int
+		wantErr       error
+		wantTokenLeft bool
+	}{
+		{"should take the token inside the window", false, 0, nil, false},
+		{"should not take the token outside of the window", false, 1, gomodbump.ErrOutsideWindow, true},
+		{"should give the token back when the window ended while waiting", true, 2, gomodbump.ErrOutsideWindow, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			limiter := ratelimit.New(ratelimit.Config{Count: 1, Per: 20 * time.Millisecond})
+
+			if tt.tokenTaken && !limiter.Allow() {
+				t.Fatal("got false want true for the first action")
+			}
+
+			// The window ends at the check with the number, 0 never ends it.
+			checks := 0
+			checkWindow := func() error {
+				checks++
+
+				if checks == tt.windowEnds {
+					return gomodbump.ErrOutsideWindow
+				}
+
+				return nil
+			}
+
+			err := gomodbump.WaitForLimit(context.Background(), limiter, checkWindow)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got '%v' want '%v'", err, tt.wantErr)
+			}
+
+			if tokenLeft := limiter.Allow(); tokenLeft != tt.wantTokenLeft {
+				t.Errorf("got token left '%v' want '%v'", tokenLeft, tt.wantTokenLeft)
+			}
+		})
+	}
+}
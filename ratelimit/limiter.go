@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Config limits an action to a count per duration, e.g. 20 per hour. Burst is how many actions can happen
+// at once after a quiet period, it defaults to 1 which spaces the actions evenly. A zero count is unlimited.
+type Config struct {
+	Count int           `yaml:"count"`
+	Per   time.Duration `yaml:"per"`
+	Burst int           `yaml:"burst"`
+}
+
+// IsEnabled returns true if the action is limited.
+func (c Config) IsEnabled() bool {
+	return c.Count > 0
+}
+
+// Validate returns an error if any of the settings are invalid.
+func (c Config) Validate() error {
+	if c.Count < 0 || c.Burst < 0 {
+		return fmt.Errorf("invalid rate limit %d per %v with burst %d, the count and burst can not be negative", c.Count, c.Per, c.Burst)
+	}
+
+	if c.IsEnabled() && c.Per <= 0 {
+		return fmt.Errorf("invalid rate limit %d per %v, the duration is required", c.Count, c.Per)
+	}
+
+	return nil
+}
+
+// Limiter is a token bucket that is shared by all the workers. A nil limiter is unlimited.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// New returns a limiter for the configuration or nil if it is not enabled. The bucket starts full.
+func New(conf Config) *Limiter {
+	if !conf.IsEnabled() {
+		return nil
+	}
+
+	burst := conf.Burst
+	if burst == 0 {
+		burst = 1
+	}
+
+	return &Limiter{
+		interval: conf.Per / time.Duration(conf.Count),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Allow takes a token and returns true if the action is allowed now, it does not wait.
+func (l *Limiter) Allow() bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+
+	return true
+}
+
+// Wait blocks until the action is allowed or the context is cancelled.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	wait := l.reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.Release()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token and returns how long to wait until it is available. Tokens are taken in advance so
+// waiting workers are allowed in the order they arrived.
+func (l *Limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// refill adds the tokens for the time passed since the last refill, up to the burst.
+func (l *Limiter) refill(now time.Time) {
+	if now.After(l.last) {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		l.last = now
+	}
+
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// Release gives back a token that was taken but not used.
+func (l *Limiter) Release() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
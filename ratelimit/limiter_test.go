@@ -0,0 +1,106 @@
+// nolint:scopelint
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/ratelimit"
+)
+
+func TestConfigValidate(t *testing.T) {
+	var tests = []struct {
+		testName string
+		conf     ratelimit.Config
+		wantErr  bool
+	}{
+		{"should allow no limit", ratelimit.Config{}, false},
+		{"should allow a count per duration", ratelimit.Config{Count: 20, Per: time.Hour}, false},
+		{"should require a duration", ratelimit.Config{Count: 20}, true},
+		{"should not allow a negative count", ratelimit.Config{Count: -1, Per: time.Hour}, true},
+		{"should not allow a negative burst", ratelimit.Config{Count: 1, Per: time.Hour, Burst: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.conf.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error '%v' want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	interval := 50 * time.Millisecond
+	limiter := ratelimit.New(ratelimit.Config{Count: 2, Per: 2 * interval, Burst: 2})
+
+	start := time.Now()
+
+	for n := 0; n < 3; n++ {
+		err := limiter.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The burst is allowed at once and the third action waits for a token.
+	if elapsed := time.Since(start); elapsed < interval*9/10 {
+		t.Errorf("got %v want at least %v", elapsed, interval)
+	}
+}
+
+func TestLimiterWaitCancelled(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{Count: 1, Per: time.Hour})
+
+	err := limiter.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = limiter.Wait(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("got error '%v' want '%v'", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNilLimiterWait(t *testing.T) {
+	var limiter *ratelimit.Limiter
+
+	if ratelimit.New(ratelimit.Config{}) != limiter {
+		t.Error("got a limiter want nil when not enabled")
+	}
+
+	err := limiter.Wait(context.Background())
+	if err != nil {
+		t.Errorf("got error '%v' want nil", err)
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{Count: 2, Per: time.Hour, Burst: 2})
+
+	for n, want := range []bool{true, true, false} {
+		if got := limiter.Allow(); got != want {
+			t.Errorf("got %v want %v for action %d", got, want, n+1)
+		}
+	}
+}
+
+func TestLimiterRelease(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{Count: 1, Per: time.Hour})
+
+	if !limiter.Allow() {
+		t.Fatal("got false want true for the first action")
+	}
+
+	limiter.Release()
+
+	if !limiter.Allow() {
+		t.Error("got false want true once the token was given back")
+	}
+}
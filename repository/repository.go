@@ -3,8 +3,8 @@ package repository
 import (
 	"path/filepath"
 
-	"github.com/Masterminds/semver"
 	"github.com/go-git/go-git/v5"
+	"github.com/ryancurrah/gomodbump/version"
 )
 
 // SCM is the kind of scm.
@@ -13,22 +13,191 @@ type SCM string
 // BitbucketServer is a scm type.
 var BitbucketServer SCM = "bitbucketserver"
 
+// Local is a scm type for a directory that is already checked out, it is bumped in place.
+var Local SCM = "local"
+
 // VCS is the kind of vcs.
 type VCS string
 
 // Git is a vcs type.
 var Git VCS = "git"
 
+// UpdateKind is the kind of requirement that was updated.
+type UpdateKind string
+
+var (
+	// DirectUpdate is an update of a module the main module imports.
+	DirectUpdate UpdateKind = "direct"
+	// IndirectUpdate is an update of a module marked '// indirect' in the go.mod file.
+	IndirectUpdate UpdateKind = "indirect"
+	// ToolUpdate is an update of a module providing a tool, declared with a tool directive or imported in tools.go.
+	ToolUpdate UpdateKind = "tool"
+	// ReplaceUpdate is an update of the version a module is replaced with by a replace directive.
+	ReplaceUpdate UpdateKind = "replace"
+)
+
 // Update is a module that can be updated.
 type Update struct {
 	Module     string
-	OldVersion *semver.Version
-	NewVersion *semver.Version
+	OldVersion *version.Version
+	NewVersion *version.Version
+	Kind       UpdateKind
+	Retraction string
+	Advisories Advisories
+	Group      string
+
+	// Replacement and ReplacedVersion are the new module path and the old version, if any, of a replace update.
+	Replacement     string
+	ReplacedVersion string
+}
+
+// IsRetracted returns true if the update moves off a retracted version.
+func (u *Update) IsRetracted() bool {
+	return u.Retraction != ""
 }
 
 // Updates is a list of modules that can be updated.
 type Updates []*Update
 
+// GetKind returns the updates of the kind provided.
+func (u Updates) GetKind(kind UpdateKind) Updates {
+	updates := make(Updates, 0, len(u))
+
+	for n := range u {
+		if u[n].Kind == kind {
+			updates = append(updates, u[n])
+		}
+	}
+
+	return updates
+}
+
+// Advisory is a security advisory fixed by an update.
+type Advisory struct {
+	ID       string
+	Aliases  []string
+	Summary  string
+	Severity string
+}
+
+// Advisories is a list of security advisories.
+type Advisories []*Advisory
+
+// GetGroup returns the updates in the group provided, an empty group returns the updates not in a group.
+func (u Updates) GetGroup(group string) Updates {
+	updates := make(Updates, 0, len(u))
+
+	for n := range u {
+		if u[n].Group == group {
+			updates = append(updates, u[n])
+		}
+	}
+
+	return updates
+}
+
+// GetGroups returns the names of the groups the updates are in, in the order they first appear.
+func (u Updates) GetGroups() []string {
+	groups := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for n := range u {
+		if u[n].Group == "" || seen[u[n].Group] {
+			continue
+		}
+
+		seen[u[n].Group] = true
+		groups = append(groups, u[n].Group)
+	}
+
+	return groups
+}
+
+// GetAdvisories returns the security advisories fixed by the updates.
+func (u Updates) GetAdvisories() Advisories {
+	advisories := make(Advisories, 0)
+
+	for n := range u {
+		advisories = append(advisories, u[n].Advisories...)
+	}
+
+	return advisories
+}
+
+// Deprecation is a required module that was deprecated by its author.
+type Deprecation struct {
+	Module  string
+	Message string
+}
+
+// Deprecations is a list of deprecated modules.
+type Deprecations []*Deprecation
+
+// Violation is a required module version that is not allowed by the repository's gomodguard configuration.
+type Violation struct {
+	Module  string
+	Version string
+	Reason  string
+}
+
+// Violations is a list of gomodguard violations.
+type Violations []*Violation
+
+// GoVersionUpdate is a change to the go directive or toolchain line of the go.mod file.
+type GoVersionUpdate struct {
+	OldGo        string
+	NewGo        string
+	OldToolchain string
+	NewToolchain string
+}
+
+// Replacement is a module replaced by a replace directive in the go.mod file.
+type Replacement struct {
+	Module             string
+	Version            string
+	Replacement        string
+	ReplacementVersion string
+	UpstreamVersion    string
+
+	// Advisories are the vulnerabilities of the replacement version.
+	Advisories Advisories
+}
+
+// IsLocal returns true if the module is replaced with a directory.
+func (r *Replacement) IsLocal() bool {
+	return r.ReplacementVersion == ""
+}
+
+// IsFork returns true if the module is replaced with a different module.
+func (r *Replacement) IsFork() bool {
+	return !r.IsLocal() && r.Replacement != r.Module
+}
+
+// IsBehindUpstream returns true if the module is replaced with a fork that is behind the upstream module.
+func (r *Replacement) IsBehindUpstream() bool {
+	return r.UpstreamVersion != ""
+}
+
+// IsVulnerable returns true if the replacement version has vulnerabilities.
+func (r *Replacement) IsVulnerable() bool {
+	return len(r.Advisories) > 0
+}
+
+// Replacements is a list of replaced modules.
+type Replacements []*Replacement
+
+// BumpResult is what changed and what was found when bumping a repository.
+type BumpResult struct {
+	Updates      Updates
+	Deprecations Deprecations
+	Violations   Violations
+	GoVersion    *GoVersionUpdate
+	Replacements Replacements
+
+	// NewFiles are the untracked files to commit with the changes to the tracked files.
+	NewFiles []string
+}
+
 // Repository is a VCS repository.
 type Repository struct {
 	Name              string
@@ -45,6 +214,12 @@ type Repository struct {
 	Pushed            bool
 	PullRequestOpened bool
 	Updates           Updates
+	Deprecations      Deprecations
+	Violations        Violations
+	GoVersion         *GoVersionUpdate
+	Replacements      Replacements
+	NewFiles          []string
+	Reviewers         []string
 	PullRequestID     int64
 }
 
@@ -55,9 +230,14 @@ func (r *Repository) SetCloned(gitRepo *git.Repository) {
 }
 
 // SetBumped repository state.
-func (r *Repository) SetBumped(updates Updates) {
+func (r *Repository) SetBumped(result *BumpResult) {
 	r.Bumped = true
-	r.Updates = updates
+	r.Updates = result.Updates
+	r.Deprecations = result.Deprecations
+	r.Violations = result.Violations
+	r.GoVersion = result.GoVersion
+	r.Replacements = result.Replacements
+	r.NewFiles = result.NewFiles
 }
 
 // SetPushed repository state.
@@ -71,8 +251,12 @@ func (r *Repository) SetPullRequest(id int64) {
 	r.PullRequestID = id
 }
 
-// ClonePath returns the string path to clone to.
+// ClonePath returns the string path to clone to. It is the directory itself for a local repository.
 func (r *Repository) ClonePath() string {
+	if r.SCM == Local {
+		return r.BaseDir
+	}
+
 	return filepath.Join(r.BaseDir, string(r.SCM), r.Parent, r.Name)
 }
 
@@ -113,6 +297,12 @@ func (r *Repository) ResetState() {
 	r.Pushed = false
 	r.PullRequestOpened = false
 	r.Updates = nil
+	r.Deprecations = nil
+	r.Violations = nil
+	r.GoVersion = nil
+	r.Replacements = nil
+	r.NewFiles = nil
+	r.Reviewers = nil
 	r.SourceBranch = ""
 	r.TargetBranch = ""
 	r.PullRequestID = 0
@@ -129,6 +319,17 @@ func NewRepository(name, url, parent string, scm SCM, vcs VCS) *Repository {
 	}
 }
 
+// NewLocalRepository returns a repository for a directory that is already checked out. It is only bumped, it is
+// never cloned, pushed or has a pull request created.
+func NewLocalRepository(dir string) *Repository {
+	return &Repository{
+		Name:    filepath.Base(dir),
+		BaseDir: dir,
+		SCM:     Local,
+		Cloned:  true,
+	}
+}
+
 // Repositories a list of VCS repositories.
 type Repositories []*Repository
 
@@ -144,3 +345,16 @@ func (r Repositories) GetSavable() Repositories {
 
 	return savableRepos
 }
+
+// GetByName returns the repositories with the name.
+func (r Repositories) GetByName(name string) Repositories {
+	repos := make(Repositories, 0, 1)
+
+	for n := range r {
+		if r[n].Name == name {
+			repos = append(repos, r[n])
+		}
+	}
+
+	return repos
+}
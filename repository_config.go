@@ -0,0 +1,61 @@
+package gomodbump
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/schedule"
+	"gopkg.in/yaml.v2"
+)
+
+const repositoryConfigFilename = ".gomodbump.yaml"
+
+// RepositoryConfig are the settings a repository can set in its own .gomodbump.yaml file. They are merged
+// over the central configuration unless repository configuration files are forbidden.
+type RepositoryConfig struct {
+	bump.RepositoryConfig `yaml:",inline"`
+	Reviewers             []string        `yaml:"reviewers"`
+	TargetBranch          string          `yaml:"target_branch"`
+	Schedule              schedule.Window `yaml:"schedule"`
+}
+
+// Validate returns an error if any of the repository settings are invalid.
+func (c RepositoryConfig) Validate() error {
+	err := c.RepositoryConfig.Validate()
+	if err != nil {
+		return err
+	}
+
+	return c.Schedule.Validate()
+}
+
+// loadRepositoryConfig reads the .gomodbump.yaml file from the root of the cloned repository. An empty
+// configuration is returned if the repository does not have one.
+func loadRepositoryConfig(repo *repository.Repository) (RepositoryConfig, error) {
+	repoConf := RepositoryConfig{}
+
+	data, err := ioutil.ReadFile(filepath.Join(repo.ClonePath(), repositoryConfigFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return repoConf, nil
+		}
+
+		return repoConf, fmt.Errorf("repo '%s': unable to read %s: %s", repo.Name, repositoryConfigFilename, err)
+	}
+
+	err = yaml.Unmarshal(data, &repoConf)
+	if err != nil {
+		return repoConf, fmt.Errorf("repo '%s': unable to parse %s: %s", repo.Name, repositoryConfigFilename, err)
+	}
+
+	err = repoConf.Validate()
+	if err != nil {
+		return repoConf, fmt.Errorf("repo '%s': invalid %s: %s", repo.Name, repositoryConfigFilename, err)
+	}
+
+	return repoConf, nil
+}
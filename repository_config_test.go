@@ -0,0 +1,178 @@
+// nolint:scopelint
+package gomodbump_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump"
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
+func TestLoadRepositoryConfig(t *testing.T) {
+	var tests = []struct {
+		testName      string
+		repoConf      string
+		wantReviewers []string
+		wantErr       bool
+	}{
+		{"should return an empty configuration without a file", "", nil, false},
+		{"should parse the file", "reviewers: [jsmith]\ntarget_branch: develop\n", []string{"jsmith"}, false},
+		{"should fail with invalid yaml", "reviewers: [jsmith\n", nil, true},
+		{"should fail with an invalid constraint", "constraints:\n  git.acme.com/lib: \"<< 2\"\n", nil, true},
+		{"should fail with an invalid schedule", "schedule:\n  days: [someday]\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := t.TempDir()
+
+			if tt.repoConf != "" {
+				writeRepositoryConfig(t, dir, tt.repoConf)
+			}
+
+			repoConf, err := gomodbump.LoadRepositoryConfig(repository.NewLocalRepository(dir))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error '%v' want error '%v'", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(repoConf.Reviewers, tt.wantReviewers) {
+				t.Errorf("got '%v' want '%v'", repoConf.Reviewers, tt.wantReviewers)
+			}
+		})
+	}
+}
+
+func TestApplyRepositoryConfig(t *testing.T) {
+	// A day that is not today, for a schedule the run is outside of.
+	otherDay := time.Now().UTC().Add(48 * time.Hour).Weekday().String()
+
+	var tests = []struct {
+		testName         string
+		forbid           bool
+		masterConf       string
+		developConf      string
+		wantInSchedule   bool
+		wantTargetBranch string
+		wantReviewers    []string
+		wantConstraint   string
+	}{
+		{
+			"should use the central configuration when repository configuration is forbidden",
+			true,
+			"reviewers: [master]\nconstraints:\n  git.acme.com/lib: \"< 1.5.0\"\ntarget_branch: develop\n",
+			"",
+			true,
+			"master",
+			nil,
+			"< 2.0.0",
+		},
+		{
+			"should merge the file of the target branch",
+			false,
+			"reviewers: [master]\nconstraints:\n  git.acme.com/lib: \"< 1.5.0\"\n",
+			"",
+			true,
+			"master",
+			[]string{"master"},
+			"< 1.5.0",
+		},
+		{
+			"should merge the file of the target branch it sets instead",
+			false,
+			"reviewers: [master]\nconstraints:\n  git.acme.com/lib: \"< 1.5.0\"\ntarget_branch: develop\n",
+			"reviewers: [develop]\nconstraints:\n  git.acme.com/lib: \"< 1.4.0\"\ntarget_branch: master\n",
+			true,
+			"develop",
+			[]string{"develop"},
+			"< 1.4.0",
+		},
+		{
+			"should use the central configuration when the target branch it sets has no file",
+			false,
+			"reviewers: [master]\ntarget_branch: develop\n",
+			"",
+			true,
+			"develop",
+			nil,
+			"< 2.0.0",
+		},
+		{
+			"should use the schedule of the target branch it sets",
+			false,
+			"target_branch: develop\n",
+			fmt.Sprintf("schedule:\n  days: [%s]\n", otherDay),
+			false,
+			"develop",
+			nil,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := t.TempDir()
+
+			origin := newOrigin(t, filepath.Join(dir, "origin"), newGoMod("git.acme.com/api", "v1.0.0"))
+
+			runGit(t, origin, "checkout", "-q", "-b", "develop")
+
+			if tt.developConf != "" {
+				writeRepositoryConfig(t, origin, tt.developConf)
+				runGit(t, origin, "add", "-A")
+				runGit(t, origin, "commit", "-q", "-m", "develop configuration")
+			}
+
+			runGit(t, origin, "checkout", "-q", "master")
+
+			writeRepositoryConfig(t, origin, tt.masterConf)
+			runGit(t, origin, "add", "-A")
+			runGit(t, origin, "commit", "-q", "-m", "master configuration")
+
+			conf := newConfiguration(dir)
+			conf.General.ForbidRepositoryConfig = tt.forbid
+			conf.Bump.Constraints = map[string]string{"git.acme.com/lib": "< 2.0.0"}
+
+			gmb := newGoModBump(t, conf, &fakeSCM{}, &fakeBumper{}, &fakeStorage{})
+
+			repo := repository.NewRepository("api", origin, "acme", repository.BitbucketServer, repository.Git)
+			repo.BaseDir = conf.GetWorkDir()
+			repo.TargetBranch = "master"
+
+			cloneOrigin(t, repo)
+
+			bumpConf, inSchedule, err := gmb.ApplyRepositoryConfig(context.Background(), repo)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if inSchedule != tt.wantInSchedule {
+				t.Errorf("got in schedule '%v' want '%v'", inSchedule, tt.wantInSchedule)
+			}
+
+			if repo.TargetBranch != tt.wantTargetBranch {
+				t.Errorf("got target branch '%v' want '%v'", repo.TargetBranch, tt.wantTargetBranch)
+			}
+
+			if !reflect.DeepEqual(repo.Reviewers, tt.wantReviewers) {
+				t.Errorf("got reviewers '%v' want '%v'", repo.Reviewers, tt.wantReviewers)
+			}
+
+			if constraint := bumpConf.Constraints["git.acme.com/lib"]; constraint != tt.wantConstraint {
+				t.Errorf("got constraint '%v' want '%v'", constraint, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func writeRepositoryConfig(t *testing.T, dir, repoConf string) {
+	err := ioutil.WriteFile(filepath.Join(dir, ".gomodbump.yaml"), []byte(repoConf), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+package gomodbump
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/retry"
+)
+
+// retryingSCMManager retries the SCM operations that failed with a transient error. Creating and merging a pull
+// request are not retried, they may have succeeded even though the request failed and retrying them would fail or
+// create the pull request twice.
+type retryingSCMManager struct {
+	scmManager
+	conf retry.Config
+}
+
+func (m *retryingSCMManager) GetRepositories(ctx context.Context, vcsType repository.VCS) (repository.Repositories, error) {
+	var repos repository.Repositories
+
+	err := retry.Do(ctx, m.conf, "getting repos", func() error {
+		var err error
+
+		repos, err = m.scmManager.GetRepositories(ctx, vcsType)
+
+		return err
+	})
+
+	return repos, err
+}
+
+// retryingVCSManager retries the VCS operations that failed with a transient error.
+type retryingVCSManager struct {
+	vcsManager
+	conf retry.Config
+}
+
+func (m *retryingVCSManager) Clone(ctx context.Context, repo *repository.Repository) (*git.Repository, error) {
+	var gitRepo *git.Repository
+
+	attempt := 0
+
+	err := retry.Do(ctx, m.conf, operationName(repo, "cloning"), func() error {
+		attempt++
+
+		// A clone that failed can leave a partial repository behind.
+		if attempt > 1 {
+			err := os.RemoveAll(repo.ClonePath())
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+
+		gitRepo, err = m.vcsManager.Clone(ctx, repo)
+
+		return err
+	})
+
+	return gitRepo, err
+}
+
+func (m *retryingVCSManager) Push(ctx context.Context, repo *repository.Repository) error {
+	return retry.Do(ctx, m.conf, operationName(repo, "pushing"), func() error {
+		return m.vcsManager.Push(ctx, repo)
+	})
+}
+
+func (m *retryingVCSManager) DeleteBranch(ctx context.Context, repo *repository.Repository) error {
+	return retry.Do(ctx, m.conf, operationName(repo, "deleting branch"), func() error {
+		return m.vcsManager.DeleteBranch(ctx, repo)
+	})
+}
+
+func (m *retryingVCSManager) ReadFile(ctx context.Context, url, tag, filename string) ([]byte, error) {
+	var contents []byte
+
+	err := retry.Do(ctx, m.conf, fmt.Sprintf("reading %s from %s at %s", filename, url, tag), func() error {
+		var err error
+
+		contents, err = m.vcsManager.ReadFile(ctx, url, tag, filename)
+
+		return err
+	})
+
+	return contents, err
+}
+
+func (m *retryingVCSManager) ReadBranchFile(ctx context.Context, url, branch, filename string) ([]byte, error) {
+	var contents []byte
+
+	err := retry.Do(ctx, m.conf, fmt.Sprintf("reading %s from %s at %s", filename, url, branch), func() error {
+		var err error
+
+		contents, err = m.vcsManager.ReadBranchFile(ctx, url, branch, filename)
+
+		return err
+	})
+
+	return contents, err
+}
+
+func operationName(repo *repository.Repository, operation string) string {
+	return fmt.Sprintf("repo '%s': %s", repo.Name, operation)
+}
@@ -0,0 +1,237 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = time.Second
+	defaultMaxBackoff  = 30 * time.Second
+	defaultJitter      = 0.2
+)
+
+// The patterns of transient failures that are only reported as text, e.g. by the Git transports.
+var retryablePatterns = []string{
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"i/o timeout",
+	"tls handshake timeout",
+	"unexpected eof",
+	"status code: 429",
+	"status code: 500",
+	"status code: 502",
+	"status code: 503",
+	"status code: 504",
+}
+
+// Config is how operations that failed with a transient error are retried. The backoff doubles after each
+// attempt up to the maximum backoff and is randomly changed by up to the jitter fraction. Zero values use the
+// defaults, set max attempts to 1 to disable retries.
+type Config struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	Backoff     time.Duration `yaml:"backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	Jitter      float64       `yaml:"jitter"`
+}
+
+// Validate returns an error if any of the settings are invalid.
+func (c Config) Validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("invalid retry max_attempts %d, it can not be negative", c.MaxAttempts)
+	}
+
+	if c.Backoff < 0 || c.MaxBackoff < 0 {
+		return errors.New("invalid retry backoff, it can not be negative")
+	}
+
+	if c.Jitter < 0 || c.Jitter > 1 {
+		return fmt.Errorf("invalid retry jitter %v, expected a fraction between 0 and 1", c.Jitter)
+	}
+
+	return nil
+}
+
+// GetMaxAttempts returns how many times an operation is attempted.
+func (c Config) GetMaxAttempts() int {
+	if c.MaxAttempts == 0 {
+		return defaultMaxAttempts
+	}
+
+	return c.MaxAttempts
+}
+
+// GetMaxBackoff returns the longest wait between attempts.
+func (c Config) GetMaxBackoff() time.Duration {
+	if c.MaxBackoff == 0 {
+		return defaultMaxBackoff
+	}
+
+	return c.MaxBackoff
+}
+
+// GetBackoff returns how long to wait after the attempt failed, attempts start at 1.
+func (c Config) GetBackoff(attempt int) time.Duration {
+	backoff := c.Backoff
+	if backoff == 0 {
+		backoff = defaultBackoff
+	}
+
+	maxBackoff := c.GetMaxBackoff()
+
+	for n := 1; n < attempt && backoff < maxBackoff; n++ {
+		backoff *= 2
+	}
+
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	jitter := c.Jitter
+	if jitter == 0 {
+		jitter = defaultJitter
+	}
+
+	return backoff + time.Duration(float64(backoff)*jitter*(2*rand.Float64()-1)) // nolint: gosec
+}
+
+// Do calls the operation until it succeeds, fails with an error that can not be retried, the attempts are
+// exhausted or the context is cancelled. The name of the operation is logged when it is retried. The wait
+// is the Retry-After of the response when the server sent one, the operation is not retried when it is longer
+// than the max backoff as it would fail again if it were retried sooner.
+func Do(ctx context.Context, conf Config, name string, operation func() error) error {
+	maxAttempts := conf.GetMaxAttempts()
+
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil || attempt >= maxAttempts || !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		backoff := conf.GetBackoff(attempt)
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			if statusErr.RetryAfter > conf.GetMaxBackoff() {
+				log.Printf("%s failed, not retrying as the server asked to wait %v: %s", name, statusErr.RetryAfter, err)
+				return err
+			}
+
+			backoff = statusErr.RetryAfter
+		}
+
+		log.Printf("%s failed, retrying in %v (attempt %d of %d): %s", name, backoff.Round(time.Millisecond), attempt, maxAttempts, err)
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// IsRetryable returns true if the error is transient: a network failure, a server error or too many requests.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return isRetryableStatusCode(statusErr.StatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+
+	for _, pattern := range retryablePatterns {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRetryableStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// StatusError is an error of a request that received a response, it is retried depending on the status code.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+// NewStatusError returns the error with the status code and Retry-After header of the response.
+func NewStatusError(response *http.Response, err error) *StatusError {
+	return &StatusError{
+		StatusCode: response.StatusCode,
+		RetryAfter: parseRetryAfter(response.Header.Get("Retry-After"), time.Now()),
+		Err:        err,
+	}
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// parseRetryAfter returns how long to wait from the Retry-After header, it is a number of seconds or a date.
+func parseRetryAfter(retryAfter string, now time.Time) time.Duration {
+	retryAfter = strings.TrimSpace(retryAfter)
+	if retryAfter == "" {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(retryAfter)
+	if err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(retryAfter)
+	if err != nil || !date.After(now) {
+		return 0
+	}
+
+	return date.Sub(now)
+}
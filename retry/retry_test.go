@@ -0,0 +1,150 @@
+// nolint:scopelint
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/retry"
+)
+
+func TestIsRetryable(t *testing.T) {
+	var tests = []struct {
+		testName string
+		err      error
+		want     bool
+	}{
+		{"should retry too many requests", &retry.StatusError{StatusCode: http.StatusTooManyRequests, Err: errors.New("429")}, true},
+		{"should retry a service unavailable error", &retry.StatusError{StatusCode: http.StatusServiceUnavailable, Err: errors.New("503")}, true},
+		{"should not retry a conflict", &retry.StatusError{StatusCode: http.StatusConflict, Err: errors.New("409")}, false},
+		{"should retry a wrapped connection reset", fmt.Errorf("unable to push: %w", syscall.ECONNRESET), true},
+		{"should retry a connection reset reported as text", errors.New("read tcp 10.0.0.1:443: connection reset by peer"), true},
+		{"should retry an unexpected git status code", errors.New(`unexpected client error: unexpected requesting "https://git/repo" status code: 502`), true},
+		{"should not retry authentication failures", errors.New("authentication required"), false},
+		{"should not retry a cancelled context", fmt.Errorf("unable to push: %w", context.Canceled), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			if got := retry.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	conf := retry.Config{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.1}
+	transientErr := &retry.StatusError{StatusCode: http.StatusBadGateway, Err: errors.New("502")}
+	permanentErr := &retry.StatusError{StatusCode: http.StatusNotFound, Err: errors.New("404")}
+	retryAfterErr := &retry.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Millisecond, Err: errors.New("429")}
+	retryLaterErr := &retry.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour, Err: errors.New("429")}
+
+	var tests = []struct {
+		testName     string
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{"should not retry a success", []error{nil}, 1, nil},
+		{"should retry until it succeeds", []error{transientErr, transientErr, nil}, 3, nil},
+		{"should stop after the max attempts", []error{transientErr, transientErr, transientErr, nil}, 3, transientErr},
+		{"should not retry a permanent error", []error{permanentErr, nil}, 1, permanentErr},
+		{"should retry after the time asked by the server", []error{retryAfterErr, nil}, 2, nil},
+		{"should not retry when the server asks to wait longer than the max backoff", []error{retryLaterErr, nil}, 1, retryLaterErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			attempts := 0
+
+			err := retry.Do(context.Background(), conf, "test", func() error {
+				attempts++
+				return tt.errs[attempts-1]
+			})
+
+			if err != tt.wantErr {
+				t.Errorf("got error '%v' want '%v'", err, tt.wantErr)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("got %d attempts want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDoCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+
+	err := retry.Do(ctx, retry.Config{Backoff: time.Hour}, "test", func() error {
+		attempts++
+
+		cancel()
+
+		return syscall.ECONNRESET
+	})
+
+	if err != syscall.ECONNRESET || attempts != 1 {
+		t.Errorf("got error '%v' after %d attempts want '%v' after 1 attempt", err, attempts, syscall.ECONNRESET)
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	var tests = []struct {
+		testName   string
+		retryAfter string
+		want       time.Duration
+	}{
+		{"should parse seconds", "120", 2 * time.Minute},
+		{"should parse a date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), time.Hour},
+		{"should ignore a date in the past", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+		{"should ignore an invalid value", "soon", 0},
+		{"should default to no wait", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			response := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+			response.Header.Set("Retry-After", tt.retryAfter)
+
+			got := retry.NewStatusError(response, errors.New("429")).RetryAfter
+
+			// Dates only have a precision of a second.
+			if got < tt.want-time.Second || got > tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBackoff(t *testing.T) {
+	conf := retry.Config{Backoff: time.Second, MaxBackoff: 5 * time.Second, Jitter: 0.5}
+
+	var tests = []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("should back off attempt %d", tt.attempt), func(t *testing.T) {
+			got := conf.GetBackoff(tt.attempt)
+
+			if got < tt.want/2 || got > tt.want*3/2 {
+				t.Errorf("got %v want %v with 50%% jitter", got, tt.want)
+			}
+		})
+	}
+}
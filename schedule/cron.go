@@ -0,0 +1,192 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The furthest a cron time is looked for, a schedule like February 30th never happens.
+const maxCronYears = 5
+
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+var monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Cron is a cron schedule with the standard five fields: minute, hour, day of month, month and day of week.
+// Fields are *, numbers, names of months and days, ranges, steps and lists of them, e.g. "0 9-16/2 * * mon-thu".
+// The descriptors @hourly, @daily, @weekly and @monthly are also supported.
+type Cron struct {
+	minutes     []bool
+	hours       []bool
+	daysOfMonth []bool
+	months      []bool
+	daysOfWeek  []bool
+
+	// The days match if either the day of month or day of week matches when both are restricted.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// ParseCron parses a cron schedule.
+func ParseCron(expr string) (*Cron, error) {
+	if descriptor, ok := cronDescriptors[strings.ToLower(strings.TrimSpace(expr))]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 { // nolint: gomnd
+		return nil, fmt.Errorf("invalid cron schedule '%s', expected 5 fields", expr)
+	}
+
+	cron := &Cron{
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+
+	var err error
+
+	cron.minutes, err = parseCronField(fields[0], 0, 59, nil) // nolint: gomnd
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule '%s' minute: %s", expr, err)
+	}
+
+	cron.hours, err = parseCronField(fields[1], 0, 23, nil) // nolint: gomnd
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule '%s' hour: %s", expr, err)
+	}
+
+	cron.daysOfMonth, err = parseCronField(fields[2], 1, 31, nil) // nolint: gomnd
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule '%s' day of month: %s", expr, err)
+	}
+
+	cron.months, err = parseCronField(fields[3], 1, 12, monthNames) // nolint: gomnd
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule '%s' month: %s", expr, err)
+	}
+
+	// Sunday is 0 or 7.
+	cron.daysOfWeek, err = parseCronField(fields[4], 0, 7, dayNames) // nolint: gomnd
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule '%s' day of week: %s", expr, err)
+	}
+
+	cron.daysOfWeek[0] = cron.daysOfWeek[0] || cron.daysOfWeek[7]
+
+	return cron, nil
+}
+
+// Next returns the first time of the schedule after the time, in the time's location. The zero time is
+// returned if the schedule never happens.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(maxCronYears, 0, 0)
+
+	for t.Before(end) {
+		switch {
+		case !c.months[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth[t.Day()]
+	dayOfWeek := c.daysOfWeek[t.Weekday()]
+
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+// parseCronField returns which values from min to max the field matches, indexed by value.
+func parseCronField(field string, min, max int, names []string) ([]bool, error) {
+	matches := make([]bool, max+1)
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+
+		if n := strings.Index(part, "/"); n != -1 {
+			var err error
+
+			rangePart = part[:n]
+
+			step, err = strconv.Atoi(part[n+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step '%s'", part[n+1:])
+			}
+		}
+
+		start, end := min, max
+
+		if rangePart != "*" {
+			var err error
+
+			bounds := strings.SplitN(rangePart, "-", 2) // nolint: gomnd
+
+			start, err = parseCronValue(bounds[0], min, max, names)
+			if err != nil {
+				return nil, err
+			}
+
+			end = start
+
+			if len(bounds) == 2 { // nolint: gomnd
+				end, err = parseCronValue(bounds[1], min, max, names)
+				if err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				// A start with a step, e.g. 5/15, goes up to the maximum.
+				end = max
+			}
+
+			if end < start {
+				return nil, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			matches[value] = true
+		}
+	}
+
+	return matches, nil
+}
+
+// parseCronValue returns the number of a value, names are the values from min.
+func parseCronValue(value string, min, max int, names []string) (int, error) {
+	for n, name := range names {
+		if strings.EqualFold(value, name) {
+			return min + n, nil
+		}
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < min || number > max {
+		return 0, fmt.Errorf("invalid value '%s', expected %d-%d", value, min, max)
+	}
+
+	return number, nil
+}
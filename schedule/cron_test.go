@@ -0,0 +1,69 @@
+// nolint:scopelint
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/schedule"
+)
+
+func TestCronNext(t *testing.T) {
+	// A Wednesday.
+	wednesday := time.Date(2020, time.April, 15, 10, 30, 0, 0, time.UTC)
+
+	var tests = []struct {
+		testName string
+		expr     string
+		time     time.Time
+		want     time.Time
+	}{
+		{"should run every minute", "* * * * *", wednesday, wednesday.Add(time.Minute)},
+		{"should run at the next hour", "@hourly", wednesday, time.Date(2020, time.April, 15, 11, 0, 0, 0, time.UTC)},
+		{"should run at the next step", "*/20 * * * *", wednesday, time.Date(2020, time.April, 15, 10, 40, 0, 0, time.UTC)},
+		{"should run later the same day", "0 9-16/2 * * *", wednesday, time.Date(2020, time.April, 15, 11, 0, 0, 0, time.UTC)},
+		{"should run the next day", "0 9 * * *", wednesday, time.Date(2020, time.April, 16, 9, 0, 0, 0, time.UTC)},
+		{"should run on the next listed weekday", "0 9 * * mon,tue", wednesday, time.Date(2020, time.April, 20, 9, 0, 0, 0, time.UTC)},
+		{"should run on sunday as 7", "0 0 * * 7", wednesday, time.Date(2020, time.April, 19, 0, 0, 0, 0, time.UTC)},
+		{"should run the next month", "0 0 1 * *", wednesday, time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"should run in the named month", "0 0 1 jan *", wednesday, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"should run on the day of month or week when both are set", "0 0 20 * fri", wednesday, time.Date(2020, time.April, 17, 0, 0, 0, 0, time.UTC)},
+		{"should never run on february 30th", "0 0 30 feb *", wednesday, time.Time{}},
+		{"should run in the time's location", "0 9 * * *", wednesday.In(time.FixedZone("EDT", -4*60*60)), time.Date(2020, time.April, 15, 9, 0, 0, 0, time.FixedZone("EDT", -4*60*60))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			cron, err := schedule.ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := cron.Next(tt.time); !got.Equal(tt.want) {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	var tests = []struct {
+		testName string
+		expr     string
+	}{
+		{"should require five fields", "* * * *"},
+		{"should not allow a minute out of range", "60 * * * *"},
+		{"should not allow an unknown name", "* * * * someday"},
+		{"should not allow a reversed range", "* 16-9 * * *"},
+		{"should not allow a zero step", "*/0 * * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, err := schedule.ParseCron(tt.expr)
+			if err == nil {
+				t.Error("got no error want an error")
+			}
+		})
+	}
+}
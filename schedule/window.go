@@ -0,0 +1,117 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const clockFormat = "15:04"
+
+// Window is a weekly time window, e.g. Monday to Thursday from 09:00 to 16:00 in a timezone. An empty
+// window always contains the time.
+type Window struct {
+	Days     []string `yaml:"days"`
+	Start    string   `yaml:"start"`
+	End      string   `yaml:"end"`
+	Timezone string   `yaml:"timezone"`
+}
+
+// IsEmpty returns true if the window has no restrictions.
+func (w Window) IsEmpty() bool {
+	return len(w.Days) == 0 && w.Start == "" && w.End == ""
+}
+
+// Validate returns an error if the window days, times or timezone are invalid.
+func (w Window) Validate() error {
+	_, err := w.Contains(time.Now())
+	return err
+}
+
+// Contains returns true if the time is in the window. Days are weekday names or their first three letters,
+// start and end are in 24 hour HH:MM format and the end is exclusive. An end before the start spans midnight.
+func (w Window) Contains(t time.Time) (bool, error) {
+	location := time.UTC
+
+	if w.Timezone != "" {
+		var err error
+
+		location, err = time.LoadLocation(w.Timezone)
+		if err != nil {
+			return false, fmt.Errorf("invalid schedule timezone '%s': %s", w.Timezone, err)
+		}
+	}
+
+	t = t.In(location)
+
+	if len(w.Days) > 0 {
+		inDays := false
+
+		for _, day := range w.Days {
+			weekday, err := parseWeekday(day)
+			if err != nil {
+				return false, err
+			}
+
+			if weekday == t.Weekday() {
+				inDays = true
+			}
+		}
+
+		if !inDays {
+			return false, nil
+		}
+	}
+
+	if w.Start == "" && w.End == "" {
+		return true, nil
+	}
+
+	start, err := parseClock(w.Start, "00:00")
+	if err != nil {
+		return false, err
+	}
+
+	end, err := parseClock(w.End, "24:00")
+	if err != nil {
+		return false, err
+	}
+
+	now := t.Hour()*60 + t.Minute() // nolint: gomnd
+
+	if end < start {
+		return now >= start || now < end, nil
+	}
+
+	return now >= start && now < end, nil
+}
+
+// parseClock returns the minutes since midnight of a HH:MM time.
+func parseClock(clock, defaultClock string) (int, error) {
+	if clock == "" {
+		clock = defaultClock
+	}
+
+	// time.Parse does not accept 24:00, which is the only way to express the end of the day.
+	if clock == "24:00" {
+		return 24 * 60, nil // nolint: gomnd
+	}
+
+	parsed, err := time.Parse(clockFormat, clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule time '%s', expected HH:MM", clock)
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), nil // nolint: gomnd
+}
+
+func parseWeekday(day string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if strings.EqualFold(day, name) || strings.EqualFold(day, name[:3]) {
+			return weekday, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid schedule day '%s'", day)
+}
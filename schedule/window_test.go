@@ -0,0 +1,48 @@
+// nolint:scopelint
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/schedule"
+)
+
+func TestWindowContains(t *testing.T) {
+	// A Wednesday.
+	wednesday := time.Date(2020, time.April, 15, 10, 30, 0, 0, time.UTC)
+
+	var tests = []struct {
+		testName     string
+		window       schedule.Window
+		time         time.Time
+		wantContains bool
+		wantErr      bool
+	}{
+		{"should contain any time when empty", schedule.Window{}, wednesday, true, false},
+		{"should contain a listed day", schedule.Window{Days: []string{"mon", "Wednesday"}}, wednesday, true, false},
+		{"should not contain an unlisted day", schedule.Window{Days: []string{"sat", "sun"}}, wednesday, false, false},
+		{"should contain a time between start and end", schedule.Window{Start: "09:00", End: "16:00"}, wednesday, true, false},
+		{"should not contain the end time", schedule.Window{Start: "09:00", End: "10:30"}, wednesday, false, false},
+		{"should contain a time after start over midnight", schedule.Window{Start: "22:00", End: "06:00"}, wednesday.Add(12 * time.Hour), true, false},
+		{"should not contain a time outside of a window over midnight", schedule.Window{Start: "22:00", End: "06:00"}, wednesday, false, false},
+		{"should default the end to the end of the day", schedule.Window{Start: "09:00"}, wednesday, true, false},
+		{"should use the timezone", schedule.Window{Start: "09:00", End: "10:00", Timezone: "America/Toronto"}, wednesday, false, false},
+		{"should return an error for an invalid day", schedule.Window{Days: []string{"someday"}}, wednesday, false, true},
+		{"should return an error for an invalid time", schedule.Window{Start: "9am"}, wednesday, false, true},
+		{"should return an error for an invalid timezone", schedule.Window{Timezone: "Nowhere/Town"}, wednesday, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			contains, err := tt.window.Contains(tt.time)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error '%v' want error %v", err, tt.wantErr)
+			}
+
+			if contains != tt.wantContains {
+				t.Errorf("got '%v' want '%v'", contains, tt.wantContains)
+			}
+		})
+	}
+}
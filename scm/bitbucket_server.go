@@ -11,6 +11,7 @@ import (
 	bitbucketv1 "github.com/gfleury/go-bitbucket-v1"
 	"github.com/mitchellh/mapstructure"
 	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/retry"
 )
 
 // BitbucketServerConfig is the information required to interact with Bitbucket server.
@@ -26,35 +27,19 @@ type BitbucketServerConfig struct {
 
 // BitbucketServer scm.
 type BitbucketServer struct {
-	ctx         context.Context
 	conf        BitbucketServerConfig
 	pullRequest PullRequestConfig
-	client      *bitbucketv1.APIClient
+	apiConf     *bitbucketv1.Configuration
 }
 
 // NewBitbucketServer initializes a new Bitbucket SCM manager.
 func NewBitbucketServer(pullRequestConf PullRequestConfig, conf BitbucketServerConfig, cloneType string) *BitbucketServer {
-	var ctx context.Context
-	if strings.TrimSpace(conf.Token) == "" {
-		ctx = context.WithValue(
-			context.Background(),
-			bitbucketv1.ContextBasicAuth, bitbucketv1.BasicAuth{UserName: conf.Username, Password: conf.Password},
-		)
-	} else {
-		ctx = context.Background()
-	}
-
 	conf.CloneType = cloneType
 
-	bitbucketServer := BitbucketServer{
-		ctx:         ctx,
+	return &BitbucketServer{
 		pullRequest: pullRequestConf,
 		conf:        conf,
-	}
-
-	bitbucketServer.client = bitbucketv1.NewAPIClient(
-		bitbucketServer.ctx,
-		bitbucketv1.NewConfiguration(conf.URL, func(config *bitbucketv1.Configuration) {
+		apiConf: bitbucketv1.NewConfiguration(conf.URL, func(config *bitbucketv1.Configuration) {
 			if conf.Token != "" {
 				config.AddDefaultHeader("Authorization", fmt.Sprintf("Bearer %s", conf.Token))
 			}
@@ -65,9 +50,16 @@ func NewBitbucketServer(pullRequestConf PullRequestConfig, conf BitbucketServerC
 				},
 			}
 		}),
-	)
+	}
+}
+
+// client returns an API client whose requests are cancelled with the context.
+func (b *BitbucketServer) client(ctx context.Context) *bitbucketv1.APIClient {
+	if strings.TrimSpace(b.conf.Token) == "" {
+		ctx = context.WithValue(ctx, bitbucketv1.ContextBasicAuth, bitbucketv1.BasicAuth{UserName: b.conf.Username, Password: b.conf.Password})
+	}
 
-	return &bitbucketServer
+	return bitbucketv1.NewAPIClient(ctx, b.apiConf)
 }
 
 // SCMType returns the SCM type.
@@ -76,16 +68,16 @@ func (b *BitbucketServer) SCMType() repository.SCM {
 }
 
 // GetRepositories that belong to the project.
-func (b *BitbucketServer) GetRepositories(vcsType repository.VCS) (repository.Repositories, error) {
+func (b *BitbucketServer) GetRepositories(ctx context.Context, vcsType repository.VCS) (repository.Repositories, error) {
 	log.Printf("getting repos for bitbucket-server project %s", b.conf.ProjectKey)
 
 	if vcsType != repository.Git {
 		return nil, fmt.Errorf(vcsNotSupportedMsg(vcsType))
 	}
 
-	response, err := b.client.DefaultApi.GetRepositories(b.conf.ProjectKey)
+	response, err := b.client(ctx).DefaultApi.GetRepositories(b.conf.ProjectKey)
 	if err != nil {
-		return nil, err
+		return nil, apiError(response, err)
 	}
 
 	bitbucketRepos, err := bitbucketv1.GetRepositoriesResponse(response)
@@ -108,25 +100,31 @@ func (b *BitbucketServer) GetRepositories(vcsType repository.VCS) (repository.Re
 }
 
 // CreatePullRequest against the repos provided using the strategy provided.
-func (b *BitbucketServer) CreatePullRequest(repo *repository.Repository) (int, error) {
-	return b.createPullRequest(repo)
+func (b *BitbucketServer) CreatePullRequest(ctx context.Context, repo *repository.Repository) (int, error) {
+	return b.createPullRequest(ctx, repo)
 }
 
 // MergePullRequest merges all existing pull requests that can be merged.
-func (b *BitbucketServer) MergePullRequest(repo *repository.Repository) error {
-	return b.mergePullRequest(repo)
+func (b *BitbucketServer) MergePullRequest(ctx context.Context, repo *repository.Repository) error {
+	return b.mergePullRequest(ctx, repo)
 }
 
-func (b *BitbucketServer) createPullRequest(repo *repository.Repository) (int, error) {
+func (b *BitbucketServer) createPullRequest(ctx context.Context, repo *repository.Repository) (int, error) {
 	if repo.VCS != repository.Git {
 		log.Print(vcsNotSupportedMsg(repo.VCS))
 
 		return 0, nil
 	}
 
-	response, err := b.client.DefaultApi.CreatePullRequest(b.conf.ProjectKey, repo.Name, bitbucketv1.PullRequest{
-		Title:       b.pullRequest.Title,
-		Description: b.pullRequest.Description,
+	reviewers := make([]bitbucketv1.UserWithMetadata, 0)
+	for _, reviewer := range b.pullRequest.GetReviewers(repo) {
+		reviewers = append(reviewers, bitbucketv1.UserWithMetadata{User: bitbucketv1.UserWithLinks{Name: reviewer}})
+	}
+
+	response, err := b.client(ctx).DefaultApi.CreatePullRequest(b.conf.ProjectKey, repo.Name, bitbucketv1.PullRequest{
+		Title:       b.pullRequest.GetTitle(repo),
+		Description: b.pullRequest.GetDescription(repo),
+		Reviewers:   reviewers,
 		FromRef: bitbucketv1.PullRequestRef{
 			ID: fmt.Sprintf("refs/heads/%s", repo.SourceBranch),
 			Repository: bitbucketv1.Repository{
@@ -147,7 +145,7 @@ func (b *BitbucketServer) createPullRequest(repo *repository.Repository) (int, e
 		},
 	})
 	if err != nil {
-		return 0, fmt.Errorf("repo '%s': unable to create pull request: %s", repo.Name, err)
+		return 0, fmt.Errorf("repo '%s': unable to create pull request: %w", repo.Name, apiError(response, err))
 	}
 
 	pullRequest, err := bitbucketv1.GetPullRequestResponse(response)
@@ -168,10 +166,12 @@ func getBitbucketServerCloneURL(cloneType string, cloneLinks []bitbucketv1.Clone
 	return ""
 }
 
-func (b *BitbucketServer) mergePullRequest(repo *repository.Repository) error {
-	response, err := b.client.DefaultApi.GetPullRequest(repo.Parent, repo.Name, int(repo.PullRequestID))
+func (b *BitbucketServer) mergePullRequest(ctx context.Context, repo *repository.Repository) error {
+	client := b.client(ctx)
+
+	response, err := client.DefaultApi.GetPullRequest(repo.Parent, repo.Name, int(repo.PullRequestID))
 	if err != nil {
-		return fmt.Errorf("repo '%s': unable to merge pull request #%d: %s", repo.Name, repo.PullRequestID, err)
+		return fmt.Errorf("repo '%s': unable to merge pull request #%d: %w", repo.Name, repo.PullRequestID, apiError(response, err))
 	}
 
 	pullRequest, err := bitbucketv1.GetPullRequestResponse(response)
@@ -183,9 +183,9 @@ func (b *BitbucketServer) mergePullRequest(repo *repository.Repository) error {
 		return nil
 	}
 
-	response, err = b.client.DefaultApi.CanMerge(repo.Parent, repo.Name, repo.PullRequestID)
+	response, err = client.DefaultApi.CanMerge(repo.Parent, repo.Name, repo.PullRequestID)
 	if err != nil {
-		return fmt.Errorf("repo '%s': unable to get pull request #%d 'can merge' status: %s", repo.Name, repo.PullRequestID, err)
+		return fmt.Errorf("repo '%s': unable to get pull request #%d 'can merge' status: %w", repo.Name, repo.PullRequestID, apiError(response, err))
 	}
 
 	var merge bitbucketv1.MergeGetResponse
@@ -204,14 +204,23 @@ func (b *BitbucketServer) mergePullRequest(repo *repository.Repository) error {
 	mergeMap := make(map[string]interface{})
 	mergeMap["version"] = pullRequest.Version
 
-	_, err = b.client.DefaultApi.Merge(repo.Parent, repo.Name, int(repo.PullRequestID), mergeMap, nil, []string{"application/json"})
+	response, err = client.DefaultApi.Merge(repo.Parent, repo.Name, int(repo.PullRequestID), mergeMap, nil, []string{"application/json"})
 	if err != nil {
-		return fmt.Errorf("repo '%s': unable to merge pull request #%d: %s", repo.Name, repo.PullRequestID, err)
+		return fmt.Errorf("repo '%s': unable to merge pull request #%d: %w", repo.Name, repo.PullRequestID, apiError(response, err))
 	}
 
 	return nil
 }
 
+// apiError returns the error of an API call with the status of the response, so transient failures are retried.
+func apiError(response *bitbucketv1.APIResponse, err error) error {
+	if response == nil || response.Response == nil {
+		return err
+	}
+
+	return retry.NewStatusError(response.Response, err)
+}
+
 func vcsNotSupportedMsg(vcs repository.VCS) string {
 	return fmt.Sprintf("scm '%s' does not support vcs type '%s': the following vcs types are supported [%s]", repository.BitbucketServer, vcs, repository.Git)
 }
@@ -1,5 +1,12 @@
 package scm
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryancurrah/gomodbump/repository"
+)
+
 // PullRequestStrategy is the strategy to use for creating pull
 // requests. This allows you to not overwhelm you CI.
 type PullRequestStrategy string
@@ -13,7 +20,186 @@ var (
 
 // PullRequestConfig are the options to use for creating pull requests.
 type PullRequestConfig struct {
-	Title       string `yaml:"title"`
-	Description string `yaml:"description"`
-	AutoMerge   bool   `yaml:"auto_merge"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	AutoMerge   bool     `yaml:"auto_merge"`
+	Reviewers   []string `yaml:"reviewers"`
+}
+
+// GetTitle returns the pull request title labelled with the security advisories the updates fix.
+func (c PullRequestConfig) GetTitle(repo *repository.Repository) string {
+	advisories := repo.Updates.GetAdvisories()
+	if len(advisories) == 0 {
+		return c.Title
+	}
+
+	labels := make([]string, 0, len(advisories))
+
+	for n := range advisories {
+		labels = append(labels, fmt.Sprintf("%s %s", advisories[n].ID, advisories[n].Severity))
+	}
+
+	return fmt.Sprintf("%s [security: %s]", c.Title, strings.Join(labels, ", "))
+}
+
+// GetReviewers returns the central reviewers followed by the repository's reviewers without duplicates.
+func (c PullRequestConfig) GetReviewers(repo *repository.Repository) []string {
+	reviewers := make([]string, 0, len(c.Reviewers)+len(repo.Reviewers))
+	seen := make(map[string]bool)
+
+	for _, reviewer := range append(append([]string{}, c.Reviewers...), repo.Reviewers...) {
+		if seen[reviewer] {
+			continue
+		}
+
+		seen[reviewer] = true
+		reviewers = append(reviewers, reviewer)
+	}
+
+	return reviewers
+}
+
+// GetDescription returns the pull request description followed by the modules that were updated.
+func (c PullRequestConfig) GetDescription(repo *repository.Repository) string {
+	description := &strings.Builder{}
+
+	description.WriteString(c.Description)
+
+	writeGoVersion(description, repo.GoVersion)
+
+	ungrouped := repo.Updates.GetGroup("")
+
+	writeUpdates(description, "Updated modules", ungrouped.GetKind(repository.DirectUpdate))
+	writeUpdates(description, "Updated indirect modules", ungrouped.GetKind(repository.IndirectUpdate))
+	writeUpdates(description, "Updated tools", ungrouped.GetKind(repository.ToolUpdate))
+	writeUpdates(description, "Updated replacements", ungrouped.GetKind(repository.ReplaceUpdate))
+
+	for _, group := range repo.Updates.GetGroups() {
+		writeUpdates(description, fmt.Sprintf("Updated %s modules", group), repo.Updates.GetGroup(group))
+	}
+
+	writeAdvisories(description, repo.Updates)
+	writeReplacements(description, repo.Replacements)
+	writeDeprecations(description, repo.Deprecations)
+	writeViolations(description, repo.Violations)
+
+	return strings.TrimSpace(description.String())
+}
+
+func writeGoVersion(description *strings.Builder, goVersion *repository.GoVersionUpdate) {
+	if goVersion == nil {
+		return
+	}
+
+	description.WriteString("\n\n### Go version\n")
+
+	if goVersion.NewGo != goVersion.OldGo {
+		fmt.Fprintf(description, "\n- `go` %s -> %s", goVersion.OldGo, goVersion.NewGo)
+	}
+
+	switch {
+	case goVersion.NewToolchain == goVersion.OldToolchain:
+	case goVersion.NewToolchain == "":
+		fmt.Fprintf(description, "\n- `toolchain` %s removed", goVersion.OldToolchain)
+	case goVersion.OldToolchain == "":
+		fmt.Fprintf(description, "\n- `toolchain` %s added", goVersion.NewToolchain)
+	default:
+		fmt.Fprintf(description, "\n- `toolchain` %s -> %s", goVersion.OldToolchain, goVersion.NewToolchain)
+	}
+}
+
+func writeUpdates(description *strings.Builder, title string, updates repository.Updates) {
+	if len(updates) == 0 {
+		return
+	}
+
+	fmt.Fprintf(description, "\n\n### %s\n", title)
+
+	if len(updates.GetKind(repository.ToolUpdate)) > 0 {
+		description.WriteString("\nTools were updated, generated code may need to be regenerated.\n")
+	}
+
+	for n := range updates {
+		if updates[n].Kind == repository.ReplaceUpdate {
+			fmt.Fprintf(description, "\n- `%s` => `%s` %s -> %s", updates[n].Module, updates[n].Replacement, updates[n].OldVersion, updates[n].NewVersion)
+			continue
+		}
+
+		fmt.Fprintf(description, "\n- `%s` %s -> %s", updates[n].Module, updates[n].OldVersion, updates[n].NewVersion)
+
+		if updates[n].IsRetracted() {
+			fmt.Fprintf(description, " (%s is retracted: %s)", updates[n].OldVersion, updates[n].Retraction)
+		}
+	}
+}
+
+func writeAdvisories(description *strings.Builder, updates repository.Updates) {
+	if len(updates.GetAdvisories()) == 0 {
+		return
+	}
+
+	description.WriteString("\n\n### Security advisories\n")
+
+	for n := range updates {
+		for _, advisory := range updates[n].Advisories {
+			fmt.Fprintf(description, "\n- **%s** (%s) `%s` fixed in %s", advisory.ID, advisory.Severity, updates[n].Module, updates[n].NewVersion)
+
+			if advisory.Summary != "" {
+				fmt.Fprintf(description, ": %s", advisory.Summary)
+			}
+
+			if len(advisory.Aliases) > 0 {
+				fmt.Fprintf(description, " (%s)", strings.Join(advisory.Aliases, ", "))
+			}
+		}
+	}
+}
+
+func writeReplacements(description *strings.Builder, replacements repository.Replacements) {
+	if len(replacements) == 0 {
+		return
+	}
+
+	description.WriteString("\n\n### Replaced modules\n")
+
+	for n := range replacements {
+		fmt.Fprintf(description, "\n- `%s` => `%s`", replacements[n].Module, replacements[n].Replacement)
+
+		switch {
+		case replacements[n].IsLocal():
+			description.WriteString(" (local directory, not updated)")
+		case replacements[n].IsBehindUpstream():
+			fmt.Fprintf(description, " %s (fork is behind upstream %s)", replacements[n].ReplacementVersion, replacements[n].UpstreamVersion)
+		default:
+			fmt.Fprintf(description, " %s", replacements[n].ReplacementVersion)
+		}
+
+		for _, advisory := range replacements[n].Advisories {
+			fmt.Fprintf(description, "\n  - vulnerable to **%s** (%s)", advisory.ID, advisory.Severity)
+		}
+	}
+}
+
+func writeDeprecations(description *strings.Builder, deprecations repository.Deprecations) {
+	if len(deprecations) == 0 {
+		return
+	}
+
+	description.WriteString("\n\n### Deprecated modules\n")
+
+	for n := range deprecations {
+		fmt.Fprintf(description, "\n- `%s`: %s", deprecations[n].Module, deprecations[n].Message)
+	}
+}
+
+func writeViolations(description *strings.Builder, violations repository.Violations) {
+	if len(violations) == 0 {
+		return
+	}
+
+	description.WriteString("\n\n### gomodguard violations\n")
+
+	for n := range violations {
+		fmt.Fprintf(description, "\n- `%s` %s: %s", violations[n].Module, violations[n].Version, violations[n].Reason)
+	}
 }
@@ -0,0 +1,265 @@
+package gomodbump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/schedule"
+	"golang.org/x/sync/errgroup"
+)
+
+// ServeConfig is the schedule of the serve command which runs Go Mod Bump as a long running service. The
+// repositories are processed at the times of the cron schedule that are in the maintenance window.
+type ServeConfig struct {
+	Schedule          string          `yaml:"schedule"`
+	Timezone          string          `yaml:"timezone"`
+	MaintenanceWindow schedule.Window `yaml:"maintenance_window"`
+
+	// Webhook bumps the repositories requiring a module as soon as its repository is tagged.
+	Webhook WebhookConfig `yaml:"webhook"`
+}
+
+// Validate returns an error if the schedule, timezone or maintenance window are invalid.
+func (c ServeConfig) Validate() error {
+	if c.Schedule == "" {
+		return nil
+	}
+
+	_, err := schedule.ParseCron(c.Schedule)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.getLocation()
+	if err != nil {
+		return err
+	}
+
+	return c.MaintenanceWindow.Validate()
+}
+
+func (c ServeConfig) getLocation() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid serve timezone '%s': %s", c.Timezone, err)
+	}
+
+	return location, nil
+}
+
+// maxBackoffRuns is the most times of the schedule a repository that keeps failing is skipped for.
+const maxBackoffRuns = 16
+
+// scheduler tracks when each repository should run next. Repositories that were never run, succeeded or were
+// stopped by the end of the maintenance window are due at every time of the schedule, so they are processed on
+// the next run even if runs were skipped. Repositories that keep failing are backed off.
+type scheduler struct {
+	mu       sync.Mutex
+	cron     *schedule.Cron
+	location *time.Location
+	backoffs map[string]backoff
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// backoff is when a repository that failed in a row is run next.
+type backoff struct {
+	failures int
+	nextRun  time.Time
+}
+
+func newScheduler(conf ServeConfig) (*scheduler, error) {
+	cron, err := schedule.ParseCron(conf.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := conf.getLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	return &scheduler{
+		cron:     cron,
+		location: location,
+		backoffs: map[string]backoff{},
+		now:      time.Now,
+	}, nil
+}
+
+// next returns the next time of the schedule after the time.
+func (s *scheduler) next(t time.Time) time.Time {
+	return s.cron.Next(t.In(s.location))
+}
+
+// getDue returns the repositories that are due at the time.
+func (s *scheduler) getDue(repos repository.Repositories, now time.Time) repository.Repositories {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dueRepos := make(repository.Repositories, 0, len(repos))
+
+	for n := range repos {
+		repoBackoff, ok := s.backoffs[repos[n].Name]
+		if !ok || !now.Before(repoBackoff.nextRun) {
+			dueRepos = append(dueRepos, repos[n])
+		}
+	}
+
+	return dueRepos
+}
+
+// update backs off the repositories that failed, they are run again at the next time of the schedule after the
+// first failure, then after 2, 4 and up to maxBackoffRuns times of the schedule. The backoff of the repositories
+// that were processed without failing is reset.
+func (s *scheduler) update(plan *Plan, runErr error, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failed := map[string]error{}
+
+	var errRun *RunError
+	if errors.As(runErr, &errRun) {
+		for _, failure := range errRun.Failures {
+			failed[failure.Repository] = failure.Err
+		}
+	}
+
+	for _, repoPlan := range plan.Repositories {
+		// The repository was not started because the run was interrupted.
+		if repoPlan == nil {
+			continue
+		}
+
+		err, ok := failed[repoPlan.Repository]
+		if !ok {
+			delete(s.backoffs, repoPlan.Repository)
+			continue
+		}
+
+		// The repository did not fail itself, it is run again in the next window.
+		if errors.Is(err, ErrOutsideWindow) {
+			continue
+		}
+
+		repoBackoff := s.backoffs[repoPlan.Repository]
+		repoBackoff.failures++
+
+		runs := 1
+		for n := 1; n < repoBackoff.failures && runs < maxBackoffRuns; n++ {
+			runs *= 2
+		}
+
+		repoBackoff.nextRun = now
+		for n := 0; n < runs; n++ {
+			repoBackoff.nextRun = s.next(repoBackoff.nextRun)
+		}
+
+		s.backoffs[repoPlan.Repository] = repoBackoff
+	}
+}
+
+// Serve runs the due repositories at every time of the schedule in the maintenance window and receives the
+// webhooks, if enabled, until the context is cancelled. A time is skipped when the previous run is still in
+// progress.
+func (b *GoModBump) Serve(ctx context.Context) error {
+	if b.conf.Serve.Schedule == "" && !b.conf.Serve.Webhook.IsEnabled() {
+		return errors.New("serve schedule or webhook address is required")
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	if b.conf.Serve.Schedule != "" {
+		sched, err := newScheduler(b.conf.Serve)
+		if err != nil {
+			return err
+		}
+
+		group.Go(func() error {
+			return b.serveSchedule(groupCtx, sched)
+		})
+	}
+
+	if b.conf.Serve.Webhook.IsEnabled() {
+		group.Go(func() error {
+			return b.serveWebhooks(groupCtx)
+		})
+	}
+
+	return group.Wait()
+}
+
+// serveSchedule runs the due repositories at every time of the schedule in the maintenance window until the
+// context is cancelled.
+func (b *GoModBump) serveSchedule(ctx context.Context, sched *scheduler) error {
+	for {
+		now := sched.now()
+
+		nextRun := sched.next(now)
+		if nextRun.IsZero() {
+			return fmt.Errorf("serve schedule '%s' never runs", b.conf.Serve.Schedule)
+		}
+
+		log.Printf("next run at %s", nextRun.Format(time.RFC3339))
+
+		timer := time.NewTimer(nextRun.Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		inWindow, err := b.conf.Serve.MaintenanceWindow.Contains(sched.now())
+		if err != nil {
+			return err
+		}
+
+		if !inWindow {
+			log.Print("outside of the maintenance window, skipping run")
+			continue
+		}
+
+		b.runScheduled(ctx, sched)
+	}
+}
+
+// runScheduled runs the due repositories unless a run is already in progress. The repositories are no longer
+// merged or pushed once the maintenance window ended, they are run again at the next time of the schedule.
+func (b *GoModBump) runScheduled(ctx context.Context, sched *scheduler) {
+	if !b.running.TryAcquire(1) {
+		log.Print("previous run is still in progress, skipping run")
+		return
+	}
+	defer b.running.Release(1)
+
+	now := sched.now()
+
+	plan, err := b.run(ctx, runOptions{
+		filter: func(repos repository.Repositories) (repository.Repositories, error) {
+			return sched.getDue(repos, now), nil
+		},
+		inWindow: func() (bool, error) {
+			return b.conf.Serve.MaintenanceWindow.Contains(sched.now())
+		},
+	})
+
+	if plan != nil {
+		sched.update(plan, err, now)
+	}
+
+	if err != nil {
+		ErrorLog.Printf("run failed: %s", err)
+	}
+}
@@ -0,0 +1,206 @@
+// nolint:scopelint
+package gomodbump_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/schedule"
+)
+
+func TestSchedulerGetDue(t *testing.T) {
+	runAt := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+	pushFailed := errors.New("push failed")
+
+	var tests = []struct {
+		testName string
+		// dbRuns are the errors of db at each hourly run, api never fails.
+		dbRuns  []error
+		dueAt   time.Time
+		wantDue []string
+	}{
+		{"should run the repositories that never ran", nil, runAt, []string{"api", "db"}},
+		{"should run the repositories again at the next time of the schedule", []error{nil}, runAt.Add(time.Hour), []string{"api", "db"}},
+		{"should run a repository that failed once at the next time of the schedule", []error{pushFailed}, runAt.Add(time.Hour), []string{"api", "db"}},
+		{"should skip a repository that failed twice at the next time of the schedule", []error{pushFailed, pushFailed}, runAt.Add(2 * time.Hour), []string{"api"}},
+		{"should run a repository that failed twice after two times of the schedule", []error{pushFailed, pushFailed}, runAt.Add(3 * time.Hour), []string{"api", "db"}},
+		{"should skip a repository that failed three times until four times of the schedule", []error{pushFailed, pushFailed, pushFailed}, runAt.Add(5 * time.Hour), []string{"api"}},
+		{"should run a repository that failed three times after four times of the schedule", []error{pushFailed, pushFailed, pushFailed}, runAt.Add(6 * time.Hour), []string{"api", "db"}},
+		{"should skip a repository that keeps failing at most for the max backoff", repeatErr(pushFailed, 10), runAt.Add(24 * time.Hour), []string{"api"}},
+		{"should run a repository that keeps failing after the max backoff", repeatErr(pushFailed, 10), runAt.Add(25 * time.Hour), []string{"api", "db"}},
+		{"should reset the backoff once the repository succeeded", []error{pushFailed, pushFailed, nil}, runAt.Add(3 * time.Hour), []string{"api", "db"}},
+		{"should not back off a repository stopped by the end of the window", []error{gomodbump.ErrOutsideWindow, gomodbump.ErrOutsideWindow}, runAt.Add(2 * time.Hour), []string{"api", "db"}},
+		{"should run the repositories that were not started because the run was interrupted", []error{gomodbump.ErrInterrupted}, runAt.Add(time.Hour), []string{"api", "db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			sched, err := gomodbump.NewScheduler(gomodbump.ServeConfig{Schedule: "@hourly"}, time.Now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos := repository.Repositories{
+				repository.NewRepository("api", "", "acme", repository.BitbucketServer, repository.Git),
+				repository.NewRepository("db", "", "acme", repository.BitbucketServer, repository.Git),
+			}
+
+			for n, dbErr := range tt.dbRuns {
+				plan := &gomodbump.Plan{Repositories: []*gomodbump.RepositoryPlan{{Repository: "api"}, {Repository: "db"}}}
+
+				var runErr error
+
+				switch {
+				case errors.Is(dbErr, gomodbump.ErrInterrupted):
+					plan.Repositories[1] = nil
+					runErr = dbErr
+				case dbErr != nil:
+					runErr = &gomodbump.RunError{Failures: []*gomodbump.RepositoryFailure{{Repository: "db", Stage: gomodbump.PushStage, Err: dbErr}}}
+				}
+
+				sched.Update(plan, runErr, runAt.Add(time.Duration(n)*time.Hour))
+			}
+
+			due := []string{}
+			for _, repo := range sched.GetDue(repos, tt.dueAt) {
+				due = append(due, repo.Name)
+			}
+
+			if !reflect.DeepEqual(due, tt.wantDue) {
+				t.Errorf("got '%v' want '%v'", due, tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestServeSchedule(t *testing.T) {
+	day := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	// Just before a time of the schedule so the run starts right away.
+	justBefore := -10 * time.Millisecond
+
+	var tests = []struct {
+		testName         string
+		start            time.Time
+		windowEndsAt     time.Time
+		wantListed       bool
+		wantPullRequests []string
+		wantBranches     int
+	}{
+		{
+			"should push and create pull requests inside the maintenance window",
+			day.Add(10*time.Hour + justBefore),
+			time.Time{},
+			true,
+			[]string{"api"},
+			2,
+		},
+		{
+			"should not push once the maintenance window ended during the run",
+			day.Add(10*time.Hour + justBefore),
+			day.Add(16 * time.Hour),
+			true,
+			[]string{},
+			1,
+		},
+		{
+			"should not run outside of the maintenance window",
+			day.Add(17*time.Hour + justBefore),
+			time.Time{},
+			false,
+			[]string{},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := t.TempDir()
+
+			origin := newOrigin(t, filepath.Join(dir, "origin"), newGoMod("git.acme.com/api", "v1.0.0"))
+
+			clock := &fakeClock{now: tt.start}
+			scm := &fakeSCM{repos: []*repository.Repository{repository.NewRepository("api", origin, "acme", repository.BitbucketServer, repository.Git)}}
+
+			// The window ends while the repository is bumped.
+			bumper := &fakeBumper{onBump: func(ctx context.Context) {
+				if !tt.windowEndsAt.IsZero() {
+					clock.Set(tt.windowEndsAt)
+				}
+			}}
+
+			saved := make(chan struct{}, 1)
+			storage := &fakeStorage{onSave: func() {
+				select {
+				case saved <- struct{}{}:
+				default:
+				}
+			}}
+
+			conf := newConfiguration(dir)
+			conf.Serve = gomodbump.ServeConfig{Schedule: "* * * * *", MaintenanceWindow: schedule.Window{Start: "09:00", End: "16:00"}}
+
+			sched, err := gomodbump.NewScheduler(conf.Serve, clock.Now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gmb := newGoModBump(t, conf, scm, bumper, storage)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			served := make(chan error, 1)
+
+			go func() {
+				served <- gmb.ServeSchedule(ctx, sched)
+			}()
+
+			// The first run is done once the state is saved, runs outside of the window are skipped.
+			if tt.wantListed {
+				select {
+				case <-saved:
+				case <-time.After(10 * time.Second):
+					t.Fatal("got no run want a run")
+				}
+			} else {
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			cancel()
+
+			err = <-served
+			if err != nil {
+				t.Fatalf("got '%v' want '%v'", err, nil)
+			}
+
+			if listed := scm.getListed() > 0; listed != tt.wantListed {
+				t.Errorf("got run '%v' want '%v'", listed, tt.wantListed)
+			}
+
+			if pullRequests := scm.getPullRequests(); !reflect.DeepEqual(pullRequests, tt.wantPullRequests) {
+				t.Errorf("got pull requests '%v' want '%v'", pullRequests, tt.wantPullRequests)
+			}
+
+			if branches := getBranches(t, origin); len(branches) != tt.wantBranches {
+				t.Errorf("got branches '%v' want %d branches", branches, tt.wantBranches)
+			}
+		})
+	}
+}
+
+func repeatErr(err error, count int) []error {
+	errs := make([]error, count)
+
+	for n := range errs {
+		errs[n] = err
+	}
+
+	return errs
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -29,7 +30,11 @@ func NewFileStorage(conf FileStorageConfig) *FileStorage {
 }
 
 // Save gomodbump repos to storage.
-func (s *FileStorage) Save(repos repository.Repositories) error {
+func (s *FileStorage) Save(ctx context.Context, repos repository.Repositories) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("unable to save to storage: %s", ctx.Err())
+	}
+
 	file, err := json.MarshalIndent(repos, "", "    ")
 	if err != nil {
 		return fmt.Errorf("unable to save to storage: %s", err)
@@ -44,7 +49,11 @@ func (s *FileStorage) Save(repos repository.Repositories) error {
 }
 
 // Load gomodbump repos from storage.
-func (s *FileStorage) Load() (repository.Repositories, error) {
+func (s *FileStorage) Load(ctx context.Context) (repository.Repositories, error) {
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("unable to load from storage: %s", ctx.Err())
+	}
+
 	if !fileExists(s.conf.Filename) {
 		return repository.Repositories{}, nil
 	}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -44,7 +45,7 @@ func NewS3Storage(conf S3StorageConfig) (*S3Storage, error) {
 }
 
 // Save gomodbump repos to storage.
-func (s *S3Storage) Save(repos repository.Repositories) error {
+func (s *S3Storage) Save(ctx context.Context, repos repository.Repositories) error {
 	file, err := json.MarshalIndent(repos, "", "    ")
 	if err != nil {
 		return fmt.Errorf("unable to save to storage: %s", err)
@@ -56,7 +57,7 @@ func (s *S3Storage) Save(repos repository.Repositories) error {
 		Key:    aws.String(s.conf.Filename),
 	}
 
-	_, err = s.client.PutObject(input)
+	_, err = s.client.PutObjectWithContext(ctx, input)
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
 			return aerr
@@ -69,7 +70,7 @@ func (s *S3Storage) Save(repos repository.Repositories) error {
 }
 
 // Load gomodbump repos from storage.
-func (s *S3Storage) Load() (repository.Repositories, error) {
+func (s *S3Storage) Load(ctx context.Context) (repository.Repositories, error) {
 	repos := repository.Repositories{}
 
 	input := &s3.GetObjectInput{
@@ -77,7 +78,7 @@ func (s *S3Storage) Load() (repository.Repositories, error) {
 		Key:    aws.String(s.conf.Filename),
 	}
 
-	object, err := s.client.GetObject(input)
+	object, err := s.client.GetObjectWithContext(ctx, input)
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
 			switch aerr.Code() {
@@ -1,8 +1,10 @@
 package vcs
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
@@ -16,16 +18,14 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/transport/client"
 	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/gookit/color"
 	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/retry"
+	"github.com/ryancurrah/gomodbump/version"
 	"golang.org/x/crypto/ssh"
 )
 
-var (
-	goModFilename = "go.mod"
-	goSumFilename = "go.sum"
-)
-
 // GitConfig are the options to use for Git VCS.
 type GitConfig struct {
 	SourceBranch      string `yaml:"source_branch"`
@@ -37,13 +37,15 @@ type GitConfig struct {
 	Username          string `yaml:"-"`
 	Password          string `yaml:"-"`
 	Token             string `yaml:"-"`
+
+	// Progress prints the progress of cloning and pushing to stdout.
+	Progress bool `yaml:"-"`
 }
 
 // Git is a version control system supported by gomodbump.
 type Git struct {
-	conf        GitConfig
-	auth        transport.AuthMethod
-	colorWriter ColorWriter
+	conf GitConfig
+	auth transport.AuthMethod
 }
 
 // NewGit initializes a new VCS manager.
@@ -85,7 +87,6 @@ func NewGit(conf GitConfig, authType string) (*Git, error) {
 				Username: conf.Username,
 				Password: conf.Password,
 			},
-			colorWriter: ColorWriter{Color: color.LightBlue},
 		}, nil
 	}
 }
@@ -106,21 +107,183 @@ func (g *Git) VCSType() repository.VCS {
 }
 
 // Clone all the repos provided and return the ones that successfully cloned.
-func (g *Git) Clone(repo *repository.Repository) (*git.Repository, error) {
-	return g.clone(repo)
+func (g *Git) Clone(ctx context.Context, repo *repository.Repository) (*git.Repository, error) {
+	return g.clone(ctx, repo)
 }
 
 // Push changed files.
-func (g *Git) Push(repo *repository.Repository) error {
-	return g.push(repo)
+func (g *Git) Push(ctx context.Context, repo *repository.Repository) error {
+	return g.push(ctx, repo)
 }
 
 // DeleteBranch from remote.
-func (g *Git) DeleteBranch(repo *repository.Repository) error {
-	return g.deleteBranch(repo)
+func (g *Git) DeleteBranch(ctx context.Context, repo *repository.Repository) error {
+	return g.deleteBranch(ctx, repo)
+}
+
+// ReadFile returns the contents of a file at the tag of a remote repository. Only the tag is cloned, in memory.
+func (g *Git) ReadFile(ctx context.Context, url, tag, filename string) ([]byte, error) {
+	gitRepo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           url,
+		ReferenceName: plumbing.NewTagReferenceName(tag),
+		SingleBranch:  true,
+		Depth:         1,
+		Auth:          g.auth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to git clone %s at %s: %w", url, tag, transportError(err))
+	}
+
+	ref, err := gitRepo.Tag(tag)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from %s at %s: %s", filename, url, tag, err)
+	}
+
+	commit, err := getTagCommit(gitRepo, ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from %s at %s: %s", filename, url, tag, err)
+	}
+
+	return readCommitFile(commit, url, tag, filename)
+}
+
+// ReadBranchFile returns the contents of a file at the head of the branch of a remote repository. Only the last
+// commit of the branch is cloned, in memory. A missing file is object.ErrFileNotFound.
+func (g *Git) ReadBranchFile(ctx context.Context, url, branch, filename string) ([]byte, error) {
+	gitRepo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           url,
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+		SingleBranch:  true,
+		Depth:         1,
+		Auth:          g.auth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to git clone %s at %s: %w", url, branch, transportError(err))
+	}
+
+	head, err := gitRepo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from %s at %s: %s", filename, url, branch, err)
+	}
+
+	commit, err := gitRepo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from %s at %s: %s", filename, url, branch, err)
+	}
+
+	return readCommitFile(commit, url, branch, filename)
+}
+
+// readCommitFile returns the contents of a file at the commit of the reference of a remote repository.
+func readCommitFile(commit *object.Commit, url, ref, filename string) ([]byte, error) {
+	file, err := commit.File(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from %s at %s: %w", filename, url, ref, err)
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from %s at %s: %s", filename, url, ref, err)
+	}
+
+	return []byte(contents), nil
+}
+
+// IsReleased returns true if the file on the target branch of the cloned repository is the same as at its latest
+// version tag, or the repository has no version tags. Changes to the file that are not released yet return false.
+func (g *Git) IsReleased(repo *repository.Repository, filename string) (bool, error) {
+	gitRepo := repo.GitRepo
+
+	latestVersion, latestTag, err := getLatestVersionTag(gitRepo)
+	if err != nil {
+		return false, fmt.Errorf("repo '%s': unable to get latest tag: %s", repo.Name, err)
+	}
+
+	if latestVersion == nil {
+		return true, nil
+	}
+
+	branchRef, err := gitRepo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, repo.TargetBranch), true)
+	if err != nil {
+		return false, fmt.Errorf("repo '%s': unable to get branch %s: %s", repo.Name, repo.TargetBranch, err)
+	}
+
+	branchCommit, err := gitRepo.CommitObject(branchRef.Hash())
+	if err != nil {
+		return false, fmt.Errorf("repo '%s': unable to get branch %s: %s", repo.Name, repo.TargetBranch, err)
+	}
+
+	tagCommit, err := getTagCommit(gitRepo, latestTag.Hash())
+	if err != nil {
+		return false, fmt.Errorf("repo '%s': unable to get tag %s: %s", repo.Name, latestVersion, err)
+	}
+
+	branchFile, err := branchCommit.File(filename)
+	if err != nil {
+		return false, fmt.Errorf("repo '%s': unable to read %s from branch %s: %s", repo.Name, filename, repo.TargetBranch, err)
+	}
+
+	tagFile, err := tagCommit.File(filename)
+	if err == object.ErrFileNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("repo '%s': unable to read %s from tag %s: %s", repo.Name, filename, latestVersion, err)
+	}
+
+	return branchFile.Hash == tagFile.Hash, nil
+}
+
+// getLatestVersionTag returns the highest version tagged in the repository and its tag, or nil if there are none.
+func getLatestVersionTag(gitRepo *git.Repository) (*version.Version, *plumbing.Reference, error) {
+	tags, err := gitRepo.Tags()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		latestVersion *version.Version
+		latestTag     *plumbing.Reference
+	)
+
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !strings.HasPrefix(name, "v") {
+			return nil
+		}
+
+		tagVersion, err := version.Parse(name)
+		if err != nil {
+			return nil
+		}
+
+		if latestVersion == nil || tagVersion.GreaterThan(latestVersion) {
+			latestVersion = tagVersion
+			latestTag = ref
+		}
+
+		return nil
+	})
+
+	return latestVersion, latestTag, err
+}
+
+// getTagCommit returns the commit of a lightweight or annotated tag.
+func getTagCommit(gitRepo *git.Repository, hash plumbing.Hash) (*object.Commit, error) {
+	tagObject, err := gitRepo.TagObject(hash)
+	if err == plumbing.ErrObjectNotFound {
+		return gitRepo.CommitObject(hash)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tagObject.Commit()
 }
 
-func (g *Git) clone(repo *repository.Repository) (*git.Repository, error) {
+func (g *Git) clone(ctx context.Context, repo *repository.Repository) (*git.Repository, error) {
 	if repo.SourceBranch == "" {
 		repo.SourceBranch = g.GetSourceBranch()
 	}
@@ -130,15 +293,16 @@ func (g *Git) clone(repo *repository.Repository) (*git.Repository, error) {
 	}
 
 	cloneOpts := git.CloneOptions{
-		URL:          repo.URL,
-		SingleBranch: true,
-		Auth:         g.auth,
-		Progress:     g.colorWriter,
+		URL:           repo.URL,
+		ReferenceName: plumbing.NewBranchReferenceName(repo.TargetBranch),
+		SingleBranch:  true,
+		Auth:          g.auth,
+		Progress:      g.progress(),
 	}
 
-	gitRepo, err := git.PlainClone(repo.ClonePath(), false, &cloneOpts)
+	gitRepo, err := git.PlainCloneContext(ctx, repo.ClonePath(), false, &cloneOpts)
 	if err != nil {
-		return nil, fmt.Errorf("repo '%s': unable to git clone, skipping: %s", repo.Name, err)
+		return nil, fmt.Errorf("repo '%s': unable to git clone, skipping: %w", repo.Name, transportError(err))
 	}
 
 	worktree, err := gitRepo.Worktree()
@@ -159,7 +323,7 @@ func (g *Git) clone(repo *repository.Repository) (*git.Repository, error) {
 	return gitRepo, nil
 }
 
-func (g *Git) push(repo *repository.Repository) error {
+func (g *Git) push(ctx context.Context, repo *repository.Repository) error {
 	log.Printf("repo '%s': pushing commits to remote", repo.Name)
 
 	worktree, err := repo.GitRepo.Worktree()
@@ -167,36 +331,68 @@ func (g *Git) push(repo *repository.Repository) error {
 		return fmt.Errorf("repo '%s': unable to push, skipping: %s", repo.Name, err)
 	}
 
-	_, err = worktree.Add(goModFilename)
+	// Post bump hooks can change more than the go.mod and go.sum files.
+	status, err := worktree.Status()
 	if err != nil {
 		return fmt.Errorf("repo '%s': unable to push, skipping: %s", repo.Name, err)
 	}
 
-	_, err = worktree.Add(goSumFilename)
+	// The changes are already committed when a push that failed is retried.
+	if !status.IsClean() {
+		err = g.commit(repo, worktree, status)
+		if err != nil {
+			return fmt.Errorf("repo '%s': unable to push, skipping: %s", repo.Name, err)
+		}
+	}
+
+	err = repo.GitRepo.PushContext(ctx, &git.PushOptions{Auth: g.auth, Progress: g.progress()})
 	if err != nil {
-		return fmt.Errorf("repo '%s': unable to push, skipping: %s", repo.Name, err)
+		return fmt.Errorf("repo '%s': unable to push, skipping: %w", repo.Name, transportError(err))
+	}
+
+	return nil
+}
+
+// commit stages the changes to the tracked files and the new files of the repository, other untracked files
+// such as build outputs are left out. Nothing is committed when there is nothing to stage.
+func (g *Git) commit(repo *repository.Repository, worktree *git.Worktree, status git.Status) error {
+	newFiles := make(map[string]bool, len(repo.NewFiles))
+
+	for _, newFile := range repo.NewFiles {
+		newFiles[newFile] = true
 	}
 
-	_, err = worktree.Commit(g.conf.CommitMessage, &git.CommitOptions{
+	staged := 0
+
+	for path, fileStatus := range status {
+		if fileStatus.Worktree == git.Unmodified || (fileStatus.Worktree == git.Untracked && !newFiles[path]) {
+			continue
+		}
+
+		_, err := worktree.Add(path)
+		if err != nil {
+			return err
+		}
+
+		staged++
+	}
+
+	if staged == 0 {
+		return nil
+	}
+
+	_, err := worktree.Commit(g.conf.CommitMessage, &git.CommitOptions{
 		Author: &object.Signature{
 			Name:  g.conf.CommitAuthorName,
 			Email: g.conf.CommitAuthorEmail,
 			When:  time.Now(),
 		},
 	})
-	if err != nil {
-		return fmt.Errorf("repo '%s': unable to push, skipping: %s", repo.Name, err)
-	}
 
-	err = repo.GitRepo.Push(&git.PushOptions{Auth: g.auth, Progress: g.colorWriter})
-	if err != nil {
-		return fmt.Errorf("repo '%s': unable to push, skipping: %s", repo.Name, err)
-	}
-
-	return nil
+	return err
 }
 
-func (g *Git) deleteBranch(repo *repository.Repository) error {
+func (g *Git) deleteBranch(ctx context.Context, repo *repository.Repository) error {
 	var branchExistsInRemote bool
 
 	remote, err := repo.GitRepo.Remote("origin")
@@ -206,7 +402,7 @@ func (g *Git) deleteBranch(repo *repository.Repository) error {
 
 	remoteRefs, err := remote.List(&git.ListOptions{Auth: g.auth})
 	if err != nil {
-		return fmt.Errorf("repo '%s': fetching unable to delete branch %s: %w", repo.Name, repo.SourceBranch, err)
+		return fmt.Errorf("repo '%s': fetching unable to delete branch %s: %w", repo.Name, repo.SourceBranch, transportError(err))
 	}
 
 	for n := range remoteRefs {
@@ -221,13 +417,13 @@ func (g *Git) deleteBranch(repo *repository.Repository) error {
 		return nil
 	}
 
-	err = repo.GitRepo.Push(&git.PushOptions{
+	err = repo.GitRepo.PushContext(ctx, &git.PushOptions{
 		Auth:     g.auth,
 		RefSpecs: []config.RefSpec{config.RefSpec(fmt.Sprintf(":refs/heads/%s", repo.SourceBranch))},
-		Progress: g.colorWriter,
+		Progress: g.progress(),
 	})
 	if err != nil {
-		return fmt.Errorf("repo '%s': unable to delete branch %s: %w", repo.Name, repo.SourceBranch, err)
+		return fmt.Errorf("repo '%s': unable to delete branch %s: %w", repo.Name, repo.SourceBranch, transportError(err))
 	}
 
 	log.Printf("repo '%s': branch %s cleaned up successfully", repo.Name, repo.SourceBranch)
@@ -235,6 +431,31 @@ func (g *Git) deleteBranch(repo *repository.Repository) error {
 	return nil
 }
 
+// transportError returns the error with the status of the HTTP response when the remote responded with an
+// unexpected status code, so transient failures are retried.
+func transportError(err error) error {
+	unexpectedErr, ok := err.(*plumbing.UnexpectedError)
+	if !ok {
+		return err
+	}
+
+	httpErr, ok := unexpectedErr.Err.(*githttp.Err)
+	if !ok || httpErr.Response == nil {
+		return err
+	}
+
+	return retry.NewStatusError(httpErr.Response, err)
+}
+
+// progress returns the writer of the clone and push progress, it is nil unless the progress is printed.
+func (g *Git) progress() io.Writer {
+	if !g.conf.Progress {
+		return nil
+	}
+
+	return ColorWriter{Color: color.LightBlue}
+}
+
 // ColorWriter writes output to stdout using the chosen color.
 type ColorWriter struct {
 	Color color.Color
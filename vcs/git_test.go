@@ -0,0 +1,163 @@
+// nolint:scopelint
+package vcs_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/vcs"
+)
+
+// newOrigin creates a repository with a commit of the files on the master branch to clone from.
+func newOrigin(t *testing.T, dir string, files map[string]string) string {
+	origin := filepath.Join(dir, "origin")
+
+	for name, content := range files {
+		writeFile(t, filepath.Join(origin, name), content)
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "master"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@acme.com", "commit", "-q", "-m", "initial commit"},
+		{"config", "receive.denyCurrentBranch", "ignore"},
+	} {
+		output, err := exec.Command("git", append([]string{"-C", origin}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %s: %s", strings.Join(args, " "), output, err)
+		}
+	}
+
+	return origin
+}
+
+func writeFile(t *testing.T, name, content string) {
+	err := os.MkdirAll(filepath.Dir(name), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(name, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// getCommitFiles returns the files of the latest commit of the branch in the repository.
+func getCommitFiles(t *testing.T, path, branch string) map[string]string {
+	gitRepo, err := git.PlainOpen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := gitRepo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit, err := gitRepo.CommitObject(ref.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+
+	iter, err := commit.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = iter.ForEach(func(file *object.File) error {
+		content, err := file.Contents()
+		files[file.Name] = content
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+func TestGitPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "vcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	origin := newOrigin(t, dir, map[string]string{
+		"go.mod":       "module git.acme.com/api\n\nrequire git.acme.com/lib v1.0.0\n",
+		"mocks/lib.go": "package mocks\n",
+		"old.go":       "package api\n",
+		".gitignore":   "*.log\n",
+	})
+
+	gitVCS, err := vcs.NewGit(vcs.GitConfig{TargetBranch: "master", SourceBranch: "gomodbump", CommitMessage: "bump", CommitAuthorName: "gomodbump", CommitAuthorEmail: "gomodbump@acme.com"}, "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &repository.Repository{Name: "api", URL: origin, SCM: repository.Local, BaseDir: filepath.Join(dir, "clone")}
+
+	gitRepo, err := gitVCS.Clone(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.SetCloned(gitRepo)
+
+	clone := repo.ClonePath()
+
+	writeFile(t, filepath.Join(clone, "go.mod"), "module git.acme.com/api\n\nrequire git.acme.com/lib v1.1.0\n")
+	writeFile(t, filepath.Join(clone, "go.sum"), "git.acme.com/lib v1.1.0 h1:abc=\n")
+	writeFile(t, filepath.Join(clone, "mocks/lib.go"), "package mocks\n\n// regenerated\n")
+	writeFile(t, filepath.Join(clone, "mocks/new.go"), "package mocks\n")
+	writeFile(t, filepath.Join(clone, "api"), "build output")
+	writeFile(t, filepath.Join(clone, "build.log"), "ignored")
+
+	err = os.Remove(filepath.Join(clone, "old.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.NewFiles = []string{"go.sum", "mocks/new.go"}
+
+	err = gitVCS.Push(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	files := getCommitFiles(t, origin, repo.SourceBranch)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	want := []string{".gitignore", "go.mod", "go.sum", "mocks/lib.go", "mocks/new.go"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("got committed files %v want %v", names, want)
+	}
+
+	if !strings.Contains(files["go.mod"], "v1.1.0") || !strings.Contains(files["mocks/lib.go"], "regenerated") {
+		t.Errorf("got changes to tracked files missing from the commit: %v", files)
+	}
+}
@@ -0,0 +1,334 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	incompatibleSuffix = "+incompatible"
+	pseudoTimeFormat   = "20060102150405"
+)
+
+var (
+	semverRe = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+		`(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+	pseudoRe = regexp.MustCompile(`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+)
+
+// Version is a Go module version. Unlike a plain semantic version it keeps the
+// exact string used by the go command so pseudo-versions and +incompatible
+// versions are never rewritten.
+type Version struct {
+	original   string
+	major      string
+	minor      string
+	patch      string
+	prerelease string
+	build      string
+}
+
+// Parse a Go module version such as v1.2.3, v1.2.3-rc.1, v2.0.0+incompatible or
+// v0.0.0-20200101000000-abcdefabcdef. A missing 'v' prefix is tolerated.
+func Parse(v string) (*Version, error) {
+	original := strings.TrimSpace(v)
+	if !strings.HasPrefix(original, "v") {
+		original = "v" + original
+	}
+
+	match := semverRe.FindStringSubmatch(original)
+	if match == nil {
+		return nil, fmt.Errorf("invalid module version '%s'", v)
+	}
+
+	version := &Version{
+		original:   original,
+		major:      match[1],
+		minor:      match[2],
+		patch:      match[3],
+		prerelease: match[4],
+		build:      match[5],
+	}
+
+	if version.build != "" && version.build != strings.TrimPrefix(incompatibleSuffix, "+") {
+		return nil, fmt.Errorf("invalid module version '%s': build metadata other than +incompatible is not allowed", v)
+	}
+
+	return version, nil
+}
+
+// String returns the exact version string, this is what must be given to the go command.
+func (v *Version) String() string {
+	return v.original
+}
+
+// Major returns the major version number, e.g. 'v2'.
+func (v *Version) Major() string {
+	return "v" + v.major
+}
+
+// Prerelease returns the prerelease without the leading '-', e.g. 'rc.1'.
+func (v *Version) Prerelease() string {
+	return v.prerelease
+}
+
+// IsPrerelease returns true if the version is a tagged prerelease. Pseudo-versions are not considered prereleases.
+func (v *Version) IsPrerelease() bool {
+	return v.prerelease != "" && !v.IsPseudo()
+}
+
+// IsIncompatible returns true if the version is a +incompatible version of a module without a go.mod file.
+func (v *Version) IsIncompatible() bool {
+	return strings.HasSuffix(v.original, incompatibleSuffix)
+}
+
+// IsPseudo returns true if the version is a pseudo-version referencing an untagged commit.
+func (v *Version) IsPseudo() bool {
+	return strings.Count(v.original, "-") >= 2 && pseudoRe.MatchString(v.original) // nolint: gomnd
+}
+
+// Time returns the commit time encoded in a pseudo-version.
+func (v *Version) Time() (time.Time, bool) {
+	if !v.IsPseudo() {
+		return time.Time{}, false
+	}
+
+	timestamp := v.pseudoParts()[1]
+
+	t, err := time.Parse(pseudoTimeFormat, timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// Revision returns the commit hash encoded in a pseudo-version.
+func (v *Version) Revision() string {
+	if !v.IsPseudo() {
+		return ""
+	}
+
+	return v.pseudoParts()[2]
+}
+
+// Base returns the tagged version a pseudo-version was derived from. Nil is
+// returned for pseudo-versions without a base (vX.0.0-timestamp-hash) and for
+// versions that are not pseudo-versions.
+func (v *Version) Base() *Version {
+	if !v.IsPseudo() {
+		return nil
+	}
+
+	prefix := v.pseudoParts()[0]
+
+	base := &Version{major: v.major, minor: v.minor, patch: v.patch}
+
+	switch {
+	case prefix == "0":
+		// vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdefabcdef is based on vX.Y.Z.
+		patch := decrement(v.patch)
+		if patch == "" {
+			return nil
+		}
+
+		base.patch = patch
+	case strings.HasSuffix(prefix, ".0"):
+		// vX.Y.Z-pre.0.yyyymmddhhmmss-abcdefabcdef is based on vX.Y.Z-pre.
+		base.prerelease = strings.TrimSuffix(prefix, ".0")
+	default:
+		// vX.0.0-yyyymmddhhmmss-abcdefabcdef has no base version.
+		return nil
+	}
+
+	base.original = fmt.Sprintf("v%s.%s.%s", base.major, base.minor, base.patch)
+	if base.prerelease != "" {
+		base.original += "-" + base.prerelease
+	}
+
+	if v.IsIncompatible() {
+		base.build = strings.TrimPrefix(incompatibleSuffix, "+")
+		base.original += incompatibleSuffix
+	}
+
+	return base
+}
+
+// Compare returns -1, 0 or 1 when v is lower, equal or higher than o using Go module version ordering.
+// Build metadata such as +incompatible is ignored.
+func (v *Version) Compare(o *Version) int {
+	if c := compareInt(v.major, o.major); c != 0 {
+		return c
+	}
+
+	if c := compareInt(v.minor, o.minor); c != 0 {
+		return c
+	}
+
+	if c := compareInt(v.patch, o.patch); c != 0 {
+		return c
+	}
+
+	return comparePrerelease(v.prerelease, o.prerelease)
+}
+
+// LessThan returns true if v is lower than o.
+func (v *Version) LessThan(o *Version) bool {
+	return v.Compare(o) < 0
+}
+
+// GreaterThan returns true if v is higher than o.
+func (v *Version) GreaterThan(o *Version) bool {
+	return v.Compare(o) > 0
+}
+
+// Equal returns true if v and o have the same precedence.
+func (v *Version) Equal(o *Version) bool {
+	return v.Compare(o) == 0
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v *Version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.original)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Version) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*v = *parsed
+
+	return nil
+}
+
+// pseudoParts returns the prerelease prefix, timestamp and revision of a pseudo-version.
+func (v *Version) pseudoParts() [3]string {
+	prerelease := v.prerelease
+
+	revisionIndex := strings.LastIndex(prerelease, "-")
+	revision := prerelease[revisionIndex+1:]
+	prerelease = prerelease[:revisionIndex]
+
+	timestampIndex := strings.LastIndexAny(prerelease, ".-")
+	timestamp := prerelease[timestampIndex+1:]
+
+	prefix := ""
+	if timestampIndex >= 0 {
+		prefix = prerelease[:timestampIndex]
+	}
+
+	return [3]string{prefix, timestamp, revision}
+}
+
+func compareInt(x, y string) int {
+	if x == y {
+		return 0
+	}
+
+	if len(x) < len(y) {
+		return -1
+	}
+
+	if len(x) > len(y) {
+		return 1
+	}
+
+	if x < y {
+		return -1
+	}
+
+	return 1
+}
+
+func comparePrerelease(x, y string) int {
+	if x == y {
+		return 0
+	}
+
+	// A version without a prerelease has a higher precedence.
+	if x == "" {
+		return 1
+	}
+
+	if y == "" {
+		return -1
+	}
+
+	xIdentifiers := strings.Split(x, ".")
+	yIdentifiers := strings.Split(y, ".")
+
+	for n := 0; n < len(xIdentifiers) && n < len(yIdentifiers); n++ {
+		xIdentifier, yIdentifier := xIdentifiers[n], yIdentifiers[n]
+		if xIdentifier == yIdentifier {
+			continue
+		}
+
+		xNumeric, yNumeric := isNumeric(xIdentifier), isNumeric(yIdentifier)
+
+		switch {
+		case xNumeric && yNumeric:
+			return compareInt(xIdentifier, yIdentifier)
+		case xNumeric:
+			return -1
+		case yNumeric:
+			return 1
+		case xIdentifier < yIdentifier:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	switch {
+	case len(xIdentifiers) < len(yIdentifiers):
+		return -1
+	case len(xIdentifiers) > len(yIdentifiers):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+func decrement(number string) string {
+	digits := []byte(number)
+
+	for n := len(digits) - 1; n >= 0; n-- {
+		if digits[n] > '0' {
+			digits[n]--
+
+			result := strings.TrimLeft(string(digits), "0")
+			if result == "" {
+				return "0"
+			}
+
+			return result
+		}
+
+		digits[n] = '9'
+	}
+
+	return ""
+}
@@ -0,0 +1,185 @@
+// nolint:scopelint
+package version_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		testName         string
+		version          string
+		wantString       string
+		wantPseudo       bool
+		wantIncompatible bool
+		wantPrerelease   bool
+		wantBase         string
+		wantErr          bool
+	}{
+		{
+			"should keep a release version as is",
+			"v1.2.3",
+			"v1.2.3", false, false, false, "", false,
+		},
+		{
+			"should add a missing v prefix",
+			"1.2.3",
+			"v1.2.3", false, false, false, "", false,
+		},
+		{
+			"should keep the prerelease",
+			"v1.2.3-rc.1",
+			"v1.2.3-rc.1", false, false, true, "", false,
+		},
+		{
+			"should keep the incompatible suffix",
+			"v2.0.0+incompatible",
+			"v2.0.0+incompatible", false, true, false, "", false,
+		},
+		{
+			"should keep a pseudo-version without a base",
+			"v0.0.0-20200302210943-78000ba7a073",
+			"v0.0.0-20200302210943-78000ba7a073", true, false, false, "", false,
+		},
+		{
+			"should find the base of a pseudo-version derived from a release",
+			"v1.2.4-0.20200302210943-78000ba7a073",
+			"v1.2.4-0.20200302210943-78000ba7a073", true, false, false, "v1.2.3", false,
+		},
+		{
+			"should find the base of a pseudo-version derived from a prerelease",
+			"v1.2.3-rc.1.0.20200302210943-78000ba7a073",
+			"v1.2.3-rc.1.0.20200302210943-78000ba7a073", true, false, false, "v1.2.3-rc.1", false,
+		},
+		{
+			"should find the base of an incompatible pseudo-version",
+			"v4.7.1-0.20200302210943-78000ba7a073+incompatible",
+			"v4.7.1-0.20200302210943-78000ba7a073+incompatible", true, true, false, "v4.7.0+incompatible", false,
+		},
+		{
+			"should fail on a partial version",
+			"v1.2",
+			"", false, false, false, "", true,
+		},
+		{
+			"should fail on build metadata",
+			"v1.2.3+build.1",
+			"", false, false, false, "", true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			v, err := version.Parse(tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got no error want an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("got error '%v' want no error", err)
+			}
+
+			if v.String() != tt.wantString {
+				t.Errorf("got string '%v' want '%v'", v.String(), tt.wantString)
+			}
+
+			if v.IsPseudo() != tt.wantPseudo {
+				t.Errorf("got pseudo '%v' want '%v'", v.IsPseudo(), tt.wantPseudo)
+			}
+
+			if v.IsIncompatible() != tt.wantIncompatible {
+				t.Errorf("got incompatible '%v' want '%v'", v.IsIncompatible(), tt.wantIncompatible)
+			}
+
+			if v.IsPrerelease() != tt.wantPrerelease {
+				t.Errorf("got prerelease '%v' want '%v'", v.IsPrerelease(), tt.wantPrerelease)
+			}
+
+			base := ""
+			if v.Base() != nil {
+				base = v.Base().String()
+			}
+
+			if base != tt.wantBase {
+				t.Errorf("got base '%v' want '%v'", base, tt.wantBase)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	var tests = []struct {
+		testName string
+		x        string
+		y        string
+		want     int
+	}{
+		{"should order by patch", "v1.2.3", "v1.2.10", -1},
+		{"should order a prerelease before its release", "v1.2.3-rc.1", "v1.2.3", -1},
+		{"should order prereleases numerically", "v1.2.3-rc.2", "v1.2.3-rc.10", -1},
+		{"should order a pseudo-version after its base", "v1.2.4-0.20200302210943-78000ba7a073", "v1.2.3", 1},
+		{"should order a pseudo-version before the next release", "v1.2.4-0.20200302210943-78000ba7a073", "v1.2.4", -1},
+		{"should order pseudo-versions by time", "v0.0.0-20190302210943-78000ba7a073", "v0.0.0-20200302210943-12345ba7a073", -1},
+		{"should ignore the incompatible suffix", "v2.0.0+incompatible", "v2.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			x, err := version.Parse(tt.x)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			y, err := version.Parse(tt.y)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := x.Compare(y); got != tt.want {
+				t.Errorf("got '%v' want '%v'", got, tt.want)
+			}
+
+			if got := y.Compare(x); got != -tt.want {
+				t.Errorf("got reverse '%v' want '%v'", got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionJSON(t *testing.T) {
+	var tests = []struct {
+		testName string
+		json     string
+		want     string
+	}{
+		{"should keep a pseudo-version", `"v0.0.0-20200302210943-78000ba7a073"`, `"v0.0.0-20200302210943-78000ba7a073"`},
+		{"should read versions saved without a v prefix", `"1.2.3"`, `"v1.2.3"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			v := &version.Version{}
+
+			err := json.Unmarshal([]byte(tt.json), v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("got '%s' want '%s'", got, tt.want)
+			}
+		})
+	}
+}
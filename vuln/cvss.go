@@ -0,0 +1,108 @@
+package vuln
+
+import (
+	"math"
+	"strings"
+)
+
+const cvssV3Type = "CVSS_V3"
+
+// cvssV3Weights are the weights of the CVSS v3 base metrics, see https://www.first.org/cvss/v3.1/specification-document.
+var cvssV3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvssV3ChangedScopePR are the privileges required weights when the scope is changed.
+var cvssV3ChangedScopePR = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+
+// getCVSSV3Score returns the base score of a CVSS v3 vector e.g. CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H,
+// false is returned if the vector is invalid.
+func getCVSSV3Score(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, false
+	}
+
+	metrics := make(map[string]string, len(parts)-1)
+
+	for _, part := range parts[1:] {
+		metric := strings.SplitN(part, ":", 2)
+		if len(metric) != 2 {
+			return 0, false
+		}
+
+		metrics[metric[0]] = metric[1]
+	}
+
+	scope := metrics["S"]
+	if scope != "U" && scope != "C" {
+		return 0, false
+	}
+
+	weights := make(map[string]float64, len(cvssV3Weights))
+
+	for name, values := range cvssV3Weights {
+		weight, ok := values[metrics[name]]
+		if !ok {
+			return 0, false
+		}
+
+		weights[name] = weight
+	}
+
+	if scope == "C" {
+		weights["PR"] = cvssV3ChangedScopePR[metrics["PR"]]
+	}
+
+	iss := 1 - (1-weights["C"])*(1-weights["I"])*(1-weights["A"])
+
+	impact := 6.42 * iss
+	if scope == "C" {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * weights["AV"] * weights["AC"] * weights["PR"] * weights["UI"]
+
+	if scope == "C" {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp returns the smallest number with one decimal equal to or higher than the score, as defined by CVSS v3.1
+// to avoid floating point errors.
+func roundUp(score float64) float64 {
+	scaled := int(math.Round(score * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+
+	return float64(scaled/10000+1) / 10
+}
+
+// getCVSSSeverity returns the qualitative severity rating of a CVSS score.
+func getCVSSSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return "CRITICAL"
+	case score >= 7:
+		return "HIGH"
+	case score >= 4:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	default:
+		return "NONE"
+	}
+}
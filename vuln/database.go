@@ -0,0 +1,232 @@
+package vuln
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+const mirrorTimeout = 30 * time.Second
+
+// Configuration of the vulnerability database to load. Only one of Dir or URL is used, Dir takes precedence.
+type Configuration struct {
+	Dir          string `yaml:"dir"`
+	URL          string `yaml:"url"`
+	SecurityOnly bool   `yaml:"security_only"`
+}
+
+// IsEnabled returns true if a vulnerability database is configured.
+func (c Configuration) IsEnabled() bool {
+	return c.Dir != "" || c.URL != ""
+}
+
+// Vulnerability is an entry affecting a module version.
+type Vulnerability struct {
+	Entry        *Entry
+	FixedVersion *version.Version
+}
+
+// Vulnerabilities is a list of vulnerabilities.
+type Vulnerabilities []*Vulnerability
+
+// GetFixedVersion returns the lowest version that fixes all the vulnerabilities. Nil is returned if any of the
+// vulnerabilities do not have a fix.
+func (v Vulnerabilities) GetFixedVersion() *version.Version {
+	var fixedVersion *version.Version
+
+	for n := range v {
+		if v[n].FixedVersion == nil {
+			return nil
+		}
+
+		if fixedVersion == nil || v[n].FixedVersion.GreaterThan(fixedVersion) {
+			fixedVersion = v[n].FixedVersion
+		}
+	}
+
+	return fixedVersion
+}
+
+// Database is an offline copy of a Go vulnerability database in the OSV format.
+type Database struct {
+	url     string
+	client  *http.Client
+	mu      sync.Mutex
+	entries map[string][]*Entry
+	index   map[string][]string
+	fetched map[string]bool
+}
+
+// mirrorModule is an entry of the index/modules.json file of a vulnerability database mirror.
+type mirrorModule struct {
+	Path  string `json:"path"`
+	Vulns []struct {
+		ID string `json:"id"`
+	} `json:"vulns"`
+}
+
+// NewDatabase loads a vulnerability database from a local directory of OSV JSON files, e.g. a clone of
+// https://github.com/golang/vulndb, or the module index of a mirror that serves the vuln.go.dev API.
+func NewDatabase(conf Configuration) (*Database, error) {
+	db := &Database{
+		entries: make(map[string][]*Entry),
+	}
+
+	if conf.Dir != "" {
+		return db, db.loadDir(conf.Dir)
+	}
+
+	db.url = strings.TrimSuffix(conf.URL, "/")
+	db.client = &http.Client{Timeout: mirrorTimeout}
+
+	return db, db.loadIndex()
+}
+
+// Find returns the vulnerabilities affecting the module version.
+func (d *Database) Find(module string, moduleVersion *version.Version) (Vulnerabilities, error) {
+	entries, err := d.getEntries(module)
+	if err != nil {
+		return nil, err
+	}
+
+	vulnerabilities := make(Vulnerabilities, 0)
+
+	for n := range entries {
+		affected, fixedVersion := entries[n].affects(module, moduleVersion)
+		if !affected {
+			continue
+		}
+
+		vulnerabilities = append(vulnerabilities, &Vulnerability{
+			Entry:        entries[n],
+			FixedVersion: fixedVersion,
+		})
+	}
+
+	return vulnerabilities, nil
+}
+
+func (d *Database) loadDir(dir string) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// Index files are JSON too, anything that is not an OSV entry is skipped.
+		entry := &Entry{}
+		if json.Unmarshal(data, entry) != nil || entry.ID == "" || len(entry.Affected) == 0 {
+			return nil
+		}
+
+		d.addEntry(entry)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to load vulnerability database from '%s': %s", dir, err)
+	}
+
+	return nil
+}
+
+func (d *Database) loadIndex() error {
+	mirrorModules := make([]mirrorModule, 0)
+
+	err := d.get("index/modules.json", &mirrorModules)
+	if err != nil {
+		return fmt.Errorf("unable to load vulnerability database from '%s': %s", d.url, err)
+	}
+
+	d.index = make(map[string][]string, len(mirrorModules))
+	d.fetched = make(map[string]bool)
+
+	for n := range mirrorModules {
+		for _, vuln := range mirrorModules[n].Vulns {
+			d.index[mirrorModules[n].Path] = append(d.index[mirrorModules[n].Path], vuln.ID)
+		}
+	}
+
+	return nil
+}
+
+func (d *Database) addEntry(entry *Entry) {
+	added := make(map[string]bool)
+
+	for _, affected := range entry.Affected {
+		module := affected.Package.Name
+		if affected.Package.Ecosystem != goEcosystem || added[module] {
+			continue
+		}
+
+		d.entries[module] = append(d.entries[module], entry)
+		added[module] = true
+	}
+}
+
+// getEntries returns the entries of the module, fetching them from the mirror the first time they are needed.
+func (d *Database) getEntries(module string) ([]*Entry, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.index == nil {
+		return d.entries[module], nil
+	}
+
+	ids, ok := d.index[module]
+	if !ok {
+		return d.entries[module], nil
+	}
+
+	for n := range ids {
+		// Entries affecting more than one module were added when the other module was fetched.
+		if d.fetched[ids[n]] {
+			continue
+		}
+
+		entry := &Entry{}
+
+		err := d.get(fmt.Sprintf("ID/%s.json", ids[n]), entry)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get vulnerability %s: %s", ids[n], err)
+		}
+
+		d.addEntry(entry)
+		d.fetched[ids[n]] = true
+	}
+
+	// Only fetch the entries of a module once.
+	delete(d.index, module)
+
+	return d.entries[module], nil
+}
+
+func (d *Database) get(path string, v interface{}) error {
+	response, err := d.client.Get(fmt.Sprintf("%s/%s", d.url, path))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(v)
+}
@@ -0,0 +1,85 @@
+// nolint:scopelint
+package vuln_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/version"
+	"github.com/ryancurrah/gomodbump/vuln"
+)
+
+const testEntry = `{
+	"id": "GO-2020-0001",
+	"aliases": ["CVE-2020-0001"],
+	"affected": [{
+		"package": {"name": "github.com/acme/lib", "ecosystem": "Go"},
+		"ranges": [{
+			"type": "SEMVER",
+			"events": [{"introduced": "0"}, {"fixed": "1.2.0"}, {"introduced": "1.4.0"}, {"fixed": "1.4.2"}]
+		}]
+	}],
+	"database_specific": {"severity": "HIGH"}
+}`
+
+func TestDatabaseFind(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vulndb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "GO-2020-0001.json"), []byte(testEntry), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := vuln.NewDatabase(vuln.Configuration{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		testName         string
+		module           string
+		version          string
+		wantAffected     bool
+		wantFixedVersion string
+	}{
+		{"should be affected before the first fix", "github.com/acme/lib", "v1.1.9", true, "v1.2.0"},
+		{"should not be affected at the fixed version", "github.com/acme/lib", "v1.2.0", false, ""},
+		{"should not be affected between ranges", "github.com/acme/lib", "v1.3.0", false, ""},
+		{"should be affected when reintroduced", "github.com/acme/lib", "v1.4.1", true, "v1.4.2"},
+		{"should not be affected after the last fix", "github.com/acme/lib", "v1.5.0", false, ""},
+		{"should not affect other modules", "github.com/acme/other", "v1.1.9", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			moduleVersion, err := version.Parse(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vulnerabilities, err := db.Find(tt.module, moduleVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if affected := len(vulnerabilities) > 0; affected != tt.wantAffected {
+				t.Fatalf("got affected '%v' want '%v'", affected, tt.wantAffected)
+			}
+
+			fixedVersion := ""
+			if vulnerabilities.GetFixedVersion() != nil {
+				fixedVersion = vulnerabilities.GetFixedVersion().String()
+			}
+
+			if fixedVersion != tt.wantFixedVersion {
+				t.Errorf("got fixed version '%v' want '%v'", fixedVersion, tt.wantFixedVersion)
+			}
+		})
+	}
+}
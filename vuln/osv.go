@@ -0,0 +1,155 @@
+package vuln
+
+import (
+	"strings"
+
+	"github.com/ryancurrah/gomodbump/version"
+)
+
+const (
+	goEcosystem     = "Go"
+	semverRangeType = "SEMVER"
+	unknownSeverity = "UNKNOWN"
+)
+
+// Entry is a vulnerability in the Open Source Vulnerability (OSV) format used by the Go vulnerability database.
+// Only the fields used by gomodbump are decoded, see https://ossf.github.io/osv-schema/.
+type Entry struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Details          string           `json:"details"`
+	Withdrawn        string           `json:"withdrawn"`
+	Affected         []Affected       `json:"affected"`
+	Severity         []Severity       `json:"severity"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+// Affected is a package affected by a vulnerability.
+type Affected struct {
+	Package Package `json:"package"`
+	Ranges  []Range `json:"ranges"`
+}
+
+// Package is the module affected by a vulnerability.
+type Package struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// Range is a list of events that introduce or fix a vulnerability.
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event introduces or fixes a vulnerability at a version. Versions do not have a 'v' prefix.
+type Event struct {
+	Introduced string `json:"introduced"`
+	Fixed      string `json:"fixed"`
+}
+
+// Severity is a score in a scoring system like CVSS.
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// DatabaseSpecific is additional information added by the database, GitHub advisories include a severity.
+type DatabaseSpecific struct {
+	Severity string `json:"severity"`
+	URL      string `json:"url"`
+}
+
+// GetSeverity returns the severity of the vulnerability e.g. HIGH. When the database did not include one it is
+// rated from the CVSS v3 score, UNKNOWN is returned if there is no CVSS v3 score either.
+func (e *Entry) GetSeverity() string {
+	if e.DatabaseSpecific.Severity != "" {
+		return strings.ToUpper(e.DatabaseSpecific.Severity)
+	}
+
+	for n := range e.Severity {
+		if e.Severity[n].Type != cvssV3Type {
+			continue
+		}
+
+		if score, ok := getCVSSV3Score(e.Severity[n].Score); ok {
+			return getCVSSSeverity(score)
+		}
+	}
+
+	return unknownSeverity
+}
+
+// affects returns true and the version that fixes the vulnerability if the module version is affected. The
+// fixed version is nil when no fix has been released.
+func (e *Entry) affects(module string, moduleVersion *version.Version) (bool, *version.Version) {
+	if e.Withdrawn != "" {
+		return false, nil
+	}
+
+	for _, affected := range e.Affected {
+		if affected.Package.Ecosystem != goEcosystem || affected.Package.Name != module {
+			continue
+		}
+
+		if len(affected.Ranges) == 0 {
+			return true, nil
+		}
+
+		for _, affectedRange := range affected.Ranges {
+			if affectedRange.Type != semverRangeType {
+				continue
+			}
+
+			if isAffected, fixed := rangeAffects(affectedRange, moduleVersion); isAffected {
+				return true, fixed
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// rangeAffects walks the events in order, a version is affected when it is at or after an introduced event
+// and before the following fixed event.
+func rangeAffects(affectedRange Range, moduleVersion *version.Version) (bool, *version.Version) {
+	var introduced *version.Version
+
+	affected := false
+
+	for _, event := range affectedRange.Events {
+		switch {
+		case event.Introduced != "":
+			introduced = parseEventVersion(event.Introduced)
+			affected = introduced != nil && !moduleVersion.LessThan(introduced)
+		case event.Fixed != "":
+			fixed := parseEventVersion(event.Fixed)
+			if fixed == nil {
+				continue
+			}
+
+			if affected && moduleVersion.LessThan(fixed) {
+				return true, fixed
+			}
+
+			affected = false
+		}
+	}
+
+	return affected, nil
+}
+
+func parseEventVersion(eventVersion string) *version.Version {
+	// An introduced version of 0 means the vulnerability has always existed.
+	if eventVersion == "0" {
+		eventVersion = "0.0.0-0"
+	}
+
+	parsed, err := version.Parse(eventVersion)
+	if err != nil {
+		return nil
+	}
+
+	return parsed
+}
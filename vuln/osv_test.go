@@ -0,0 +1,77 @@
+// nolint:scopelint
+package vuln_test
+
+import (
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/vuln"
+)
+
+func TestEntryGetSeverity(t *testing.T) {
+	var tests = []struct {
+		testName string
+		entry    vuln.Entry
+		want     string
+	}{
+		{
+			"should use the database severity",
+			vuln.Entry{DatabaseSpecific: vuln.DatabaseSpecific{Severity: "moderate"}, Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}}},
+			"MODERATE",
+		},
+		{
+			"should rate a critical cvss v3 score",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}}},
+			"CRITICAL",
+		},
+		{
+			"should rate a high cvss v3 score",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"}}},
+			"HIGH",
+		},
+		{
+			"should rate a medium cvss v3 score with a changed scope",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N"}}},
+			"MEDIUM",
+		},
+		{
+			"should rate a cvss v3.0 score with a changed scope and privileges required",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H"}}},
+			"CRITICAL",
+		},
+		{
+			"should rate a low cvss v3 score",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N"}}},
+			"LOW",
+		},
+		{
+			"should rate a cvss v3 score without impact as none",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N"}}},
+			"NONE",
+		},
+		{
+			"should skip other scoring systems",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"}, {Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"}}},
+			"HIGH",
+		},
+		{
+			"should be unknown with only other scoring systems",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"}}},
+			"UNKNOWN",
+		},
+		{
+			"should be unknown with an invalid cvss v3 vector",
+			vuln.Entry{Severity: []vuln.Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L"}}},
+			"UNKNOWN",
+		},
+		{"should be unknown without a severity", vuln.Entry{}, "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			severity := tt.entry.GetSeverity()
+			if severity != tt.want {
+				t.Errorf("got '%s' want '%s'", severity, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package gomodbump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/ryancurrah/gomodbump/bump"
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/webhook"
+)
+
+// webhookQueueSize is how many tags can wait for the run in progress before webhooks are refused.
+const webhookQueueSize = 100
+
+// webhookReadTimeout limits how long a client has to send a webhook.
+const webhookReadTimeout = 30 * time.Second
+
+// webhookPathPrefix is the path the webhooks of each provider are received at, e.g. /webhooks/github.
+const webhookPathPrefix = "/webhooks/"
+
+// WebhookConfig is the HTTP server of the serve command receiving tag webhooks from the SCMs. When a repository
+// is tagged the repositories requiring its module are bumped right away.
+type WebhookConfig struct {
+	Address string `yaml:"address"`
+	Secret  string `yaml:"-"`
+
+	// Modules are the module paths of the tagged repositories by repository name. The module path of a repository
+	// that is not listed is read from its go.mod file at the tag.
+	Modules map[string]string `yaml:"modules"`
+}
+
+// IsEnabled returns true if the webhook server has an address to listen on.
+func (c WebhookConfig) IsEnabled() bool {
+	return c.Address != ""
+}
+
+// serveWebhooks receives webhooks until the context is cancelled and bumps the repositories requiring the
+// module of each tagged repository, one tag at a time.
+func (b *GoModBump) serveWebhooks(ctx context.Context) error {
+	conf := b.conf.Serve.Webhook
+
+	if strings.TrimSpace(conf.Secret) == "" {
+		return errors.New("webhook secret is required")
+	}
+
+	events := make(chan *webhook.TagEvent, webhookQueueSize)
+
+	mux := http.NewServeMux()
+
+	for _, provider := range webhook.Providers {
+		mux.Handle(webhookPathPrefix+string(provider), webhook.NewHandler(provider, conf.Secret, func(event *webhook.TagEvent) error {
+			select {
+			case events <- event:
+				return nil
+			default:
+				return fmt.Errorf("too many tags waiting, unable to bump repos requiring repo '%s' %s", event.Repository, event.Tag)
+			}
+		}))
+	}
+
+	server := &http.Server{
+		Addr:        conf.Address,
+		Handler:     mux,
+		ReadTimeout: webhookReadTimeout,
+	}
+
+	errServe := make(chan error, 1)
+
+	go func() {
+		errServe <- server.ListenAndServe()
+	}()
+
+	log.Printf("listening for webhooks on %s", conf.Address)
+
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), b.conf.General.GetShutdownTimeout())
+			defer cancel()
+
+			return server.Shutdown(shutdownCtx)
+		case err := <-errServe:
+			return fmt.Errorf("webhook server failed: %w", err)
+		case event := <-events:
+			b.runTagged(ctx, event)
+		}
+	}
+}
+
+// runTagged bumps the module of the tagged repository in the repositories requiring it. It waits for the run in
+// progress, if any, and does not merge pull requests. Tags received outside of the maintenance window are left to
+// the scheduled runs, which update every module, and the repositories are no longer pushed once the window ended.
+func (b *GoModBump) runTagged(ctx context.Context, event *webhook.TagEvent) {
+	module, err := b.getTaggedModule(ctx, event)
+	if err != nil {
+		ErrorLog.Printf("repo '%s': unable to get module path of tag %s: %s", event.Repository, event.Tag, err)
+		return
+	}
+
+	err = b.running.Acquire(ctx, 1)
+	if err != nil {
+		return
+	}
+	defer b.running.Release(1)
+
+	inWindow := func() (bool, error) {
+		return b.conf.Serve.MaintenanceWindow.Contains(time.Now())
+	}
+
+	ok, err := inWindow()
+	if err != nil {
+		ErrorLog.Printf("run bumping %s failed: %s", module, err)
+		return
+	}
+
+	if !ok {
+		log.Printf("outside of the maintenance window, leaving %s to the scheduled runs, repo '%s' was tagged %s", module, event.Repository, event.Tag)
+		return
+	}
+
+	log.Printf("bumping %s in the repos requiring it, repo '%s' was tagged %s", module, event.Repository, event.Tag)
+
+	_, err = b.run(ctx, runOptions{
+		filter: func(repos repository.Repositories) (repository.Repositories, error) {
+			return b.getRequiring(ctx, repos, module), nil
+		},
+		module:   module,
+		inWindow: inWindow,
+	})
+	if err != nil {
+		ErrorLog.Printf("run bumping %s failed: %s", module, err)
+	}
+}
+
+// getRequiring returns the repositories whose go.mod file on their target branch requires the module, read
+// without cloning them. The repositories whose go.mod file can not be read are kept, they are checked once cloned.
+func (b *GoModBump) getRequiring(ctx context.Context, repos repository.Repositories, module string) repository.Repositories {
+	requiring := make(repository.Repositories, 0, len(repos))
+
+	for _, repo := range repos {
+		// The repositories with a pull request open are not bumped until it is merged by a scheduled run.
+		if !repo.IsCloneable(b.vcsManager.VCSType()) || repo.PullRequestOpened {
+			continue
+		}
+
+		targetBranch := repo.TargetBranch
+		if targetBranch == "" {
+			targetBranch = b.vcsManager.GetTargetBranch()
+		}
+
+		goMod, err := b.vcsManager.ReadBranchFile(ctx, repo.URL, targetBranch, goModFilename)
+		if errors.Is(err, object.ErrFileNotFound) {
+			continue
+		}
+
+		if err != nil {
+			log.Printf("repo '%s': unable to read %s, cloning it to check if it requires %s: %s", repo.Name, goModFilename, module, err)
+
+			requiring = append(requiring, repo)
+
+			continue
+		}
+
+		repoModule, err := bump.ParseModule(goMod)
+		if err != nil {
+			log.Printf("repo '%s': unable to parse %s, cloning it to check if it requires %s: %s", repo.Name, goModFilename, module, err)
+
+			requiring = append(requiring, repo)
+
+			continue
+		}
+
+		if repoModule.HasRequire(module) {
+			requiring = append(requiring, repo)
+		}
+	}
+
+	return requiring
+}
+
+// getTaggedModule returns the module path of the tagged repository from the configuration, or from its go.mod
+// file at the tag. The go.mod file of a tag with a directory prefix, e.g. api/v1.2.0, is in that directory.
+func (b *GoModBump) getTaggedModule(ctx context.Context, event *webhook.TagEvent) (string, error) {
+	if module, ok := b.conf.Serve.Webhook.Modules[event.Repository]; ok {
+		return module, nil
+	}
+
+	url := event.GetCloneURL(b.conf.General.CloneType)
+	if url == "" {
+		return "", errors.New("webhook has no clone url")
+	}
+
+	goModPath := goModFilename
+
+	if dir := path.Dir(event.Tag); dir != "." {
+		goModPath = path.Join(dir, goModPath)
+	}
+
+	goMod, err := b.vcsManager.ReadFile(ctx, url, event.Tag, goModPath)
+	if err != nil {
+		return "", err
+	}
+
+	return bump.ParseModulePath(goMod)
+}
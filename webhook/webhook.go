@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// maxPayloadSize is the largest webhook payload read, push payloads with many commits are a few hundred KB.
+const maxPayloadSize = 5 << 20
+
+const tagRefPrefix = "refs/tags/"
+
+// ErrInvalidSignature is returned when the webhook request is not signed with the secret.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Provider is the SCM sending the webhook.
+type Provider string
+
+var (
+	// BitbucketServer sends repo:refs_changed events signed with the X-Hub-Signature header.
+	BitbucketServer Provider = "bitbucket-server"
+	// GitHub sends push events signed with the X-Hub-Signature-256 header.
+	GitHub Provider = "github"
+	// GitLab sends tag push events with the secret token in the X-Gitlab-Token header.
+	GitLab Provider = "gitlab"
+)
+
+// Providers are the SCMs webhooks are accepted from.
+var Providers = []Provider{BitbucketServer, GitHub, GitLab}
+
+// TagEvent is a tag that was pushed to a repository.
+type TagEvent struct {
+	Provider   Provider
+	Repository string
+	Tag        string
+	HTTPURL    string
+	SSHURL     string
+}
+
+// GetCloneURL returns the URL of the repository for the clone type, http or ssh.
+func (e *TagEvent) GetCloneURL(cloneType string) string {
+	if cloneType == "ssh" && e.SSHURL != "" {
+		return e.SSHURL
+	}
+
+	return e.HTTPURL
+}
+
+// Handler receives the webhooks of a provider and calls onTag for every tag created. When onTag returns an
+// error the webhook is responded to as unavailable so the SCM can deliver it again.
+type Handler struct {
+	provider Provider
+	secret   string
+	onTag    func(event *TagEvent) error
+}
+
+// NewHandler initializes a webhook handler for the provider. Requests have to be signed with the secret.
+func NewHandler(provider Provider, secret string, onTag func(event *TagEvent) error) *Handler {
+	return &Handler{
+		provider: provider,
+		secret:   secret,
+		onTag:    onTag,
+	}
+}
+
+// ServeHTTP verifies the signature of the webhook and parses the tags from its payload. Events other than tags
+// being created are accepted and ignored.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "unable to read payload", http.StatusBadRequest)
+		return
+	}
+
+	events, err := Parse(h.provider, h.secret, r.Header, body)
+	if errors.Is(err, ErrInvalidSignature) {
+		log.Printf("%s webhook: %s", h.provider, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+
+		return
+	}
+
+	if err != nil {
+		log.Printf("%s webhook: %s", h.provider, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	for _, event := range events {
+		log.Printf("%s webhook: repo '%s' was tagged %s", h.provider, event.Repository, event.Tag)
+
+		err = h.onTag(event)
+		if err != nil {
+			log.Printf("%s webhook: %s", h.provider, err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Parse verifies the signature of the webhook and returns the tags created by it.
+func Parse(provider Provider, secret string, header http.Header, body []byte) ([]*TagEvent, error) {
+	switch provider {
+	case BitbucketServer:
+		err := verifySignature(secret, header.Get("X-Hub-Signature"), body)
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Get("X-Event-Key") != "repo:refs_changed" {
+			return nil, nil
+		}
+
+		return parseBitbucketServer(body)
+	case GitHub:
+		err := verifySignature(secret, header.Get("X-Hub-Signature-256"), body)
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Get("X-GitHub-Event") != "push" {
+			return nil, nil
+		}
+
+		return parseGitHub(body)
+	case GitLab:
+		err := verifyToken(secret, header.Get("X-Gitlab-Token"))
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Get("X-Gitlab-Event") != "Tag Push Hook" {
+			return nil, nil
+		}
+
+		return parseGitLab(body)
+	default:
+		return nil, fmt.Errorf("unsupported webhook provider '%s'", provider)
+	}
+}
+
+// verifySignature checks the signature is the hex encoded HMAC SHA-256 of the body with the secret, prefixed
+// with 'sha256='.
+func verifySignature(secret, signature string, body []byte) error {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return ErrInvalidSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint: errcheck
+
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// verifyToken checks the token is the secret, GitLab sends the secret itself instead of a signature.
+func verifyToken(secret, token string) error {
+	if token == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(token)) != 1 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+type bitbucketServerPayload struct {
+	Repository struct {
+		Slug  string `json:"slug"`
+		Links struct {
+			Clone []struct {
+				Href string `json:"href"`
+				Name string `json:"name"`
+			} `json:"clone"`
+		} `json:"links"`
+	} `json:"repository"`
+	Changes []struct {
+		Ref struct {
+			ID        string `json:"id"`
+			DisplayID string `json:"displayId"`
+			Type      string `json:"type"`
+		} `json:"ref"`
+		Type string `json:"type"`
+	} `json:"changes"`
+}
+
+func parseBitbucketServer(body []byte) ([]*TagEvent, error) {
+	payload := bitbucketServerPayload{}
+
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse bitbucket server payload: %s", err)
+	}
+
+	var httpURL, sshURL string
+
+	for _, link := range payload.Repository.Links.Clone {
+		switch link.Name {
+		case "http":
+			httpURL = link.Href
+		case "ssh":
+			sshURL = link.Href
+		}
+	}
+
+	var events []*TagEvent
+
+	for _, change := range payload.Changes {
+		if change.Type != "ADD" || change.Ref.Type != "TAG" {
+			continue
+		}
+
+		events = append(events, &TagEvent{
+			Provider:   BitbucketServer,
+			Repository: payload.Repository.Slug,
+			Tag:        strings.TrimPrefix(change.Ref.ID, tagRefPrefix),
+			HTTPURL:    httpURL,
+			SSHURL:     sshURL,
+		})
+	}
+
+	return events, nil
+}
+
+type gitHubPayload struct {
+	Ref        string `json:"ref"`
+	Created    bool   `json:"created"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		Name     string `json:"name"`
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+	} `json:"repository"`
+}
+
+func parseGitHub(body []byte) ([]*TagEvent, error) {
+	payload := gitHubPayload{}
+
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse github payload: %s", err)
+	}
+
+	if !strings.HasPrefix(payload.Ref, tagRefPrefix) || !payload.Created || payload.Deleted {
+		return nil, nil
+	}
+
+	return []*TagEvent{{
+		Provider:   GitHub,
+		Repository: payload.Repository.Name,
+		Tag:        strings.TrimPrefix(payload.Ref, tagRefPrefix),
+		HTTPURL:    payload.Repository.CloneURL,
+		SSHURL:     payload.Repository.SSHURL,
+	}}, nil
+}
+
+type gitLabPayload struct {
+	Ref     string `json:"ref"`
+	After   string `json:"after"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		GitHTTPURL        string `json:"git_http_url"`
+		GitSSHURL         string `json:"git_ssh_url"`
+	} `json:"project"`
+}
+
+// gitLabDeletedSHA is the after commit of a tag push that deleted the tag.
+const gitLabDeletedSHA = "0000000000000000000000000000000000000000"
+
+func parseGitLab(body []byte) ([]*TagEvent, error) {
+	payload := gitLabPayload{}
+
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse gitlab payload: %s", err)
+	}
+
+	if !strings.HasPrefix(payload.Ref, tagRefPrefix) || payload.After == gitLabDeletedSHA {
+		return nil, nil
+	}
+
+	return []*TagEvent{{
+		Provider:   GitLab,
+		Repository: path.Base(payload.Project.PathWithNamespace),
+		Tag:        strings.TrimPrefix(payload.Ref, tagRefPrefix),
+		HTTPURL:    payload.Project.GitHTTPURL,
+		SSHURL:     payload.Project.GitSSHURL,
+	}}, nil
+}
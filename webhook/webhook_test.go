@@ -0,0 +1,226 @@
+// nolint:scopelint
+package webhook_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ryancurrah/gomodbump/webhook"
+)
+
+const secret = "s3cr3t"
+
+const bitbucketServerPayload = `{
+	"eventKey": "repo:refs_changed",
+	"repository": {
+		"slug": "lib",
+		"links": {
+			"clone": [
+				{"href": "ssh://git@bitbucket.acme.com:7999/prj/lib.git", "name": "ssh"},
+				{"href": "https://bitbucket.acme.com/scm/prj/lib.git", "name": "http"}
+			]
+		}
+	},
+	"changes": [
+		{"ref": {"id": "refs/heads/master", "displayId": "master", "type": "BRANCH"}, "type": "UPDATE"},
+		{"ref": {"id": "refs/tags/v1.0.0", "displayId": "v1.0.0", "type": "TAG"}, "type": "DELETE"},
+		{"ref": {"id": "refs/tags/v1.1.0", "displayId": "v1.1.0", "type": "TAG"}, "type": "ADD"}
+	]
+}`
+
+const gitHubPayload = `{
+	"ref": "refs/tags/v1.1.0",
+	"created": true,
+	"deleted": false,
+	"repository": {
+		"name": "lib",
+		"clone_url": "https://github.com/acme/lib.git",
+		"ssh_url": "git@github.com:acme/lib.git"
+	}
+}`
+
+const gitLabPayload = `{
+	"object_kind": "tag_push",
+	"ref": "refs/tags/v1.1.0",
+	"after": "82b3d5ae55f7080f1e6022629cdb57bfae7cccc7",
+	"project": {
+		"path_with_namespace": "acme/lib",
+		"git_http_url": "https://gitlab.acme.com/acme/lib.git",
+		"git_ssh_url": "git@gitlab.acme.com:acme/lib.git"
+	}
+}`
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body)) // nolint: errcheck
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		testName string
+		provider webhook.Provider
+		header   map[string]string
+		body     string
+		want     []*webhook.TagEvent
+		wantErr  error
+	}{
+		{
+			"should parse the added tags of a bitbucket server event",
+			webhook.BitbucketServer,
+			map[string]string{"X-Event-Key": "repo:refs_changed", "X-Hub-Signature": sign(bitbucketServerPayload)},
+			bitbucketServerPayload,
+			[]*webhook.TagEvent{{
+				Provider:   webhook.BitbucketServer,
+				Repository: "lib",
+				Tag:        "v1.1.0",
+				HTTPURL:    "https://bitbucket.acme.com/scm/prj/lib.git",
+				SSHURL:     "ssh://git@bitbucket.acme.com:7999/prj/lib.git",
+			}},
+			nil,
+		},
+		{
+			"should ignore a bitbucket server ping",
+			webhook.BitbucketServer,
+			map[string]string{"X-Event-Key": "diagnostics:ping", "X-Hub-Signature": sign("{}")},
+			"{}",
+			nil,
+			nil,
+		},
+		{
+			"should not allow a bitbucket server event signed with another secret",
+			webhook.BitbucketServer,
+			map[string]string{"X-Event-Key": "repo:refs_changed", "X-Hub-Signature": "sha256=" + hex.EncodeToString([]byte("forged"))},
+			bitbucketServerPayload,
+			nil,
+			webhook.ErrInvalidSignature,
+		},
+		{
+			"should parse a github tag push",
+			webhook.GitHub,
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": sign(gitHubPayload)},
+			gitHubPayload,
+			[]*webhook.TagEvent{{
+				Provider:   webhook.GitHub,
+				Repository: "lib",
+				Tag:        "v1.1.0",
+				HTTPURL:    "https://github.com/acme/lib.git",
+				SSHURL:     "git@github.com:acme/lib.git",
+			}},
+			nil,
+		},
+		{
+			"should ignore a github branch push",
+			webhook.GitHub,
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": sign(`{"ref": "refs/heads/master", "created": true}`)},
+			`{"ref": "refs/heads/master", "created": true}`,
+			nil,
+			nil,
+		},
+		{
+			"should not allow a github event without a signature",
+			webhook.GitHub,
+			map[string]string{"X-GitHub-Event": "push"},
+			gitHubPayload,
+			nil,
+			webhook.ErrInvalidSignature,
+		},
+		{
+			"should parse a gitlab tag push",
+			webhook.GitLab,
+			map[string]string{"X-Gitlab-Event": "Tag Push Hook", "X-Gitlab-Token": secret},
+			gitLabPayload,
+			[]*webhook.TagEvent{{
+				Provider:   webhook.GitLab,
+				Repository: "lib",
+				Tag:        "v1.1.0",
+				HTTPURL:    "https://gitlab.acme.com/acme/lib.git",
+				SSHURL:     "git@gitlab.acme.com:acme/lib.git",
+			}},
+			nil,
+		},
+		{
+			"should ignore a gitlab tag deletion",
+			webhook.GitLab,
+			map[string]string{"X-Gitlab-Event": "Tag Push Hook", "X-Gitlab-Token": secret},
+			`{"ref": "refs/tags/v1.1.0", "after": "0000000000000000000000000000000000000000"}`,
+			nil,
+			nil,
+		},
+		{
+			"should not allow a gitlab event with another token",
+			webhook.GitLab,
+			map[string]string{"X-Gitlab-Event": "Tag Push Hook", "X-Gitlab-Token": "guess"},
+			gitLabPayload,
+			nil,
+			webhook.ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			header := http.Header{}
+
+			for key, value := range tt.header {
+				header.Set(key, value)
+			}
+
+			got, err := webhook.Parse(tt.provider, secret, header, []byte(tt.body))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error '%v' want '%v'", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var tests = []struct {
+		testName   string
+		signature  string
+		onTagErr   error
+		wantStatus int
+		wantTags   int
+	}{
+		{"should accept a signed webhook", sign(gitHubPayload), nil, http.StatusAccepted, 1},
+		{"should refuse an unsigned webhook", "", nil, http.StatusUnauthorized, 0},
+		{"should be unavailable when the tag can not be queued", sign(gitHubPayload), errors.New("queue is full"), http.StatusServiceUnavailable, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			tags := 0
+
+			handler := webhook.NewHandler(webhook.GitHub, secret, func(event *webhook.TagEvent) error {
+				tags++
+				return tt.onTagErr
+			})
+
+			request := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewBufferString(gitHubPayload))
+			request.Header.Set("X-GitHub-Event", "push")
+			request.Header.Set("X-Hub-Signature-256", tt.signature)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("got status %d want %d", recorder.Code, tt.wantStatus)
+			}
+
+			if tags != tt.wantTags {
+				t.Errorf("got %d tags want %d", tags, tt.wantTags)
+			}
+		})
+	}
+}
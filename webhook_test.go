@@ -0,0 +1,185 @@
+// nolint:scopelint
+package gomodbump_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ryancurrah/gomodbump/repository"
+	"github.com/ryancurrah/gomodbump/schedule"
+	"github.com/ryancurrah/gomodbump/webhook"
+)
+
+func TestGetTaggedModule(t *testing.T) {
+	dir := t.TempDir()
+
+	origin := newOrigin(t, filepath.Join(dir, "origin"), newGoMod("git.acme.com/lib", "v1.0.0"))
+
+	err := os.MkdirAll(filepath.Join(origin, "api"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeGoMod(t, filepath.Join(origin, "api"), "module git.acme.com/lib/api\n\ngo 1.16\n")
+	runGit(t, origin, "add", "-A")
+	runGit(t, origin, "commit", "-q", "-m", "api")
+	runGit(t, origin, "tag", "v1.2.0")
+	runGit(t, origin, "tag", "api/v0.1.0")
+
+	var tests = []struct {
+		testName   string
+		event      *webhook.TagEvent
+		wantModule string
+		wantErr    bool
+	}{
+		{
+			"should return the module of the repository from the configuration",
+			&webhook.TagEvent{Repository: "tools", Tag: "v1.0.0"},
+			"git.acme.com/tools",
+			false,
+		},
+		{
+			"should return the module of the go.mod file at the tag",
+			&webhook.TagEvent{Repository: "lib", Tag: "v1.2.0", HTTPURL: origin},
+			"git.acme.com/lib",
+			false,
+		},
+		{
+			"should return the module of the go.mod file in the directory of the tag",
+			&webhook.TagEvent{Repository: "lib", Tag: "api/v0.1.0", HTTPURL: origin},
+			"git.acme.com/lib/api",
+			false,
+		},
+		{
+			"should fail when the tag does not exist",
+			&webhook.TagEvent{Repository: "lib", Tag: "v9.9.9", HTTPURL: origin},
+			"",
+			true,
+		},
+		{
+			"should fail without a clone url",
+			&webhook.TagEvent{Repository: "lib", Tag: "v1.2.0"},
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			conf := newConfiguration(dir)
+			conf.Serve.Webhook.Modules = map[string]string{"tools": "git.acme.com/tools"}
+
+			gmb := newGoModBump(t, conf, &fakeSCM{}, &fakeBumper{}, &fakeStorage{})
+
+			module, err := gmb.GetTaggedModule(context.Background(), tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error '%v' want error '%v'", err, tt.wantErr)
+			}
+
+			if module != tt.wantModule {
+				t.Errorf("got '%v' want '%v'", module, tt.wantModule)
+			}
+		})
+	}
+}
+
+func TestRunTagged(t *testing.T) {
+	dir := t.TempDir()
+
+	// api requires the tagged module, db does not and web has a pull request open.
+	api := newOrigin(t, filepath.Join(dir, "api"), newGoMod("git.acme.com/api", "v1.0.0"))
+	db := newOrigin(t, filepath.Join(dir, "db"), "module git.acme.com/db\n\ngo 1.16\n")
+	web := newOrigin(t, filepath.Join(dir, "web"), newGoMod("git.acme.com/web", "v1.0.0"))
+	commitBranch(t, web, "pr", newGoMod("git.acme.com/web", "v1.1.0"))
+
+	scm := &fakeSCM{repos: []*repository.Repository{
+		repository.NewRepository("api", api, "acme", repository.BitbucketServer, repository.Git),
+		repository.NewRepository("db", db, "acme", repository.BitbucketServer, repository.Git),
+		repository.NewRepository("web", web, "acme", repository.BitbucketServer, repository.Git),
+	}}
+	bumper := &fakeBumper{}
+
+	storedRepo := repository.NewRepository("web", web, "acme", repository.BitbucketServer, repository.Git)
+	storedRepo.PullRequestOpened = true
+	storedRepo.PullRequestID = 7
+	storedRepo.SourceBranch = "pr"
+	storedRepo.TargetBranch = "master"
+
+	conf := newConfiguration(dir)
+	conf.Serve.Webhook.Modules = map[string]string{"lib": "git.acme.com/lib"}
+	conf.Bump.GoVersion.Minimum = "1.21"
+	conf.Bump.Replace.Update = true
+
+	gmb := newGoModBump(t, conf, scm, bumper, &fakeStorage{repos: repository.Repositories{storedRepo}})
+
+	gmb.RunTagged(context.Background(), &webhook.TagEvent{Repository: "lib", Tag: "v1.2.0"})
+
+	for _, name := range []string{"db", "web"} {
+		if goMod := bumper.getGoMod(name); goMod != "" {
+			t.Errorf("got repo '%v' bumped want it skipped", name)
+		}
+	}
+
+	targeted := bumper.getConf("api")
+
+	if !reflect.DeepEqual(targeted.TargetFilter.AllowedModules, []string{"git.acme.com/lib"}) {
+		t.Errorf("got targeted modules '%v' want '%v'", targeted.TargetFilter.AllowedModules, []string{"git.acme.com/lib"})
+	}
+
+	if targeted.GoVersion.IsEnabled() || targeted.Replace.Update {
+		t.Errorf("got go version and replace updates enabled '%v' '%v' want them disabled", targeted.GoVersion.IsEnabled(), targeted.Replace.Update)
+	}
+
+	if merged := scm.getMerged(); len(merged) != 0 {
+		t.Errorf("got merged '%v' want none", merged)
+	}
+
+	if pullRequests := scm.getPullRequests(); !reflect.DeepEqual(pullRequests, []string{"api"}) {
+		t.Errorf("got pull requests '%v' want '%v'", pullRequests, []string{"api"})
+	}
+
+	wantGoMod := newGoMod("git.acme.com/web", "v1.0.0")
+	if goMod := readGoMod(t, web, "master"); goMod != wantGoMod {
+		t.Errorf("got go.mod of web '%v' want '%v'", goMod, wantGoMod)
+	}
+
+	// Only the repositories requiring the module are cloned.
+	for _, repo := range scm.repos {
+		repo.BaseDir = conf.GetWorkDir()
+
+		_, err := os.Stat(repo.ClonePath())
+		if cloned := err == nil; cloned != (repo.Name == "api") {
+			t.Errorf("got repo '%v' cloned '%v' want '%v'", repo.Name, cloned, repo.Name == "api")
+		}
+	}
+}
+
+func TestRunTaggedOutsideWindow(t *testing.T) {
+	dir := t.TempDir()
+
+	api := newOrigin(t, filepath.Join(dir, "api"), newGoMod("git.acme.com/api", "v1.0.0"))
+
+	scm := &fakeSCM{repos: []*repository.Repository{repository.NewRepository("api", api, "acme", repository.BitbucketServer, repository.Git)}}
+	bumper := &fakeBumper{}
+
+	// A window on another day than today.
+	conf := newConfiguration(dir)
+	conf.Serve.Webhook.Modules = map[string]string{"lib": "git.acme.com/lib"}
+	conf.Serve.MaintenanceWindow = schedule.Window{Days: []string{time.Now().UTC().Add(48 * time.Hour).Weekday().String()}}
+
+	gmb := newGoModBump(t, conf, scm, bumper, &fakeStorage{})
+
+	gmb.RunTagged(context.Background(), &webhook.TagEvent{Repository: "lib", Tag: "v1.2.0"})
+
+	if goMod := bumper.getGoMod("api"); goMod != "" {
+		t.Errorf("got repo '%v' bumped want it left to the scheduled runs", "api")
+	}
+
+	if listed := scm.getListed(); listed != 0 {
+		t.Errorf("got repos listed %d times want none", listed)
+	}
+}